@@ -0,0 +1,266 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package arrowconv parses the JSON rows into Arrow record batches, so that the rows can be
+// handed over to the Arrow-based pipelines without the intermediate conversion of Row maps.
+package arrowconv
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/util/importutilv2/json"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// ParseToArrow parses the rows of a batch with the parser, and builds the record of the Arrow
+// schema, whose fields are matched with the fields of the collection by name. The values are
+// converted the same way as Parse, and appended to the Arrow arrays as they are parsed:
+//   - the scalar fields to the arrays of the corresponding types,
+//   - the Array fields to the list arrays,
+//   - the FloatVector fields to the fixed-size lists of float32, the other vector fields, which
+//     are parsed as bytes, to the fixed-size lists of uint8,
+//   - the JSON fields and the dynamic field to the string or binary arrays.
+//
+// The values missing from a row, such as the auto-generated primary key, are appended as nulls
+// if the Arrow field is nullable. The delete marker rows are not supported.
+func ParseToArrow(parser json.RowParser, raws []any, schema *arrow.Schema) (arrow.Record, error) {
+	sink := &arrowSink{
+		fields:   schema.Fields(),
+		columns:  make(map[int64]int, len(schema.Fields())),
+		appended: make([]bool, len(schema.Fields())),
+	}
+	for i, field := range sink.fields {
+		fieldID, ok := parser.FieldID(field.Name)
+		if !ok {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("the field '%s' of the arrow schema is not found in the collection schema", field.Name))
+		}
+		sink.columns[fieldID] = i
+	}
+	sink.builder = array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer sink.builder.Release()
+	sink.builder.Reserve(len(raws))
+	if err := parser.ParseToSink(raws, sink); err != nil {
+		return nil, err
+	}
+	return sink.builder.NewRecord(), nil
+}
+
+// arrowSink appends the parsed values to the builders of the Arrow arrays.
+type arrowSink struct {
+	builder *array.RecordBuilder
+	fields  []arrow.Field
+	// the index of the arrow field, keyed by the field id
+	columns map[int64]int
+	// whether the arrow field is appended by the current row
+	appended []bool
+}
+
+func (s *arrowSink) Append(fieldID int64, value any) error {
+	if fieldID == json.DeletedFieldID {
+		return merr.WrapErrImportFailed("the row is a deletion, which cannot be converted to arrow record")
+	}
+	i, ok := s.columns[fieldID]
+	if !ok || value == nil {
+		return nil
+	}
+	if err := appendValue(s.builder.Field(i), value); err != nil {
+		return merr.WrapErrImportFailed(fmt.Sprintf("failed to append the value of field '%s', error: %v", s.fields[i].Name, err))
+	}
+	s.appended[i] = true
+	return nil
+}
+
+// EndRow appends nulls for the fields missing from the row.
+func (s *arrowSink) EndRow() error {
+	for i, field := range s.fields {
+		if s.appended[i] {
+			s.appended[i] = false
+			continue
+		}
+		if !field.Nullable {
+			return merr.WrapErrImportFailed(fmt.Sprintf("value of field '%s' is missed, but the arrow field is not nullable", field.Name))
+		}
+		s.builder.Field(i).AppendNull()
+	}
+	return nil
+}
+
+// DiscardRow does nothing, the record is dropped as a whole if any row is invalid.
+func (s *arrowSink) DiscardRow() {}
+
+// appendValue appends a parsed value to the builder of the Arrow array.
+func appendValue(builder array.Builder, value any) error {
+	switch b := builder.(type) {
+	case *array.BooleanBuilder:
+		if v, ok := value.(bool); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.Int8Builder, *array.Int16Builder, *array.Int32Builder, *array.Int64Builder:
+		if v, ok := asInt64(value); ok {
+			return appendInteger(builder, v)
+		}
+	case *array.Float32Builder:
+		if v, ok := value.(float32); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.Float64Builder:
+		switch v := value.(type) {
+		case float64:
+			b.Append(v)
+			return nil
+		case float32:
+			b.Append(float64(v))
+			return nil
+		}
+	case *array.StringBuilder:
+		switch v := value.(type) {
+		case string:
+			b.Append(v)
+			return nil
+		case []byte:
+			b.Append(string(v))
+			return nil
+		}
+	case *array.BinaryBuilder:
+		switch v := value.(type) {
+		case []byte:
+			b.Append(v)
+			return nil
+		case string:
+			b.AppendString(v)
+			return nil
+		}
+	case *array.FixedSizeListBuilder:
+		return appendVector(b, value)
+	case *array.ListBuilder:
+		if v, ok := value.(*schemapb.ScalarField); ok {
+			b.Append(true)
+			for _, elem := range scalarValues(v) {
+				if err := appendValue(b.ValueBuilder(), elem); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot append the value of type '%T' to the arrow array of type '%s'", value, builder.Type())
+}
+
+// appendVector appends a parsed vector to the fixed-size list, whose size must be the number of
+// its float32 or byte elements.
+func appendVector(builder *array.FixedSizeListBuilder, value any) error {
+	size := int(builder.Type().(*arrow.FixedSizeListType).Len())
+	switch v := value.(type) {
+	case []float32:
+		values, ok := builder.ValueBuilder().(*array.Float32Builder)
+		if ok && len(v) == size {
+			builder.Append(true)
+			values.AppendValues(v, nil)
+			return nil
+		}
+	case []byte:
+		values, ok := builder.ValueBuilder().(*array.Uint8Builder)
+		if ok && len(v) == size {
+			builder.Append(true)
+			values.AppendValues(v, nil)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot append the vector of type '%T' to the arrow array of type '%s'", value, builder.Type())
+}
+
+func appendInteger(builder array.Builder, value int64) error {
+	outOfRange := func(min, max int64) error {
+		if value < min || value > max {
+			return fmt.Errorf("the value %d is out of the range of the arrow array of type '%s'", value, builder.Type())
+		}
+		return nil
+	}
+	switch b := builder.(type) {
+	case *array.Int8Builder:
+		if err := outOfRange(-1<<7, 1<<7-1); err != nil {
+			return err
+		}
+		b.Append(int8(value))
+	case *array.Int16Builder:
+		if err := outOfRange(-1<<15, 1<<15-1); err != nil {
+			return err
+		}
+		b.Append(int16(value))
+	case *array.Int32Builder:
+		if err := outOfRange(-1<<31, 1<<31-1); err != nil {
+			return err
+		}
+		b.Append(int32(value))
+	case *array.Int64Builder:
+		b.Append(value)
+	}
+	return nil
+}
+
+func asInt64(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// scalarValues returns the elements of a parsed Array value.
+func scalarValues(data *schemapb.ScalarField) []any {
+	var values []any
+	switch {
+	case data.GetBoolData() != nil:
+		for _, v := range data.GetBoolData().GetData() {
+			values = append(values, v)
+		}
+	case data.GetIntData() != nil:
+		for _, v := range data.GetIntData().GetData() {
+			values = append(values, v)
+		}
+	case data.GetLongData() != nil:
+		for _, v := range data.GetLongData().GetData() {
+			values = append(values, v)
+		}
+	case data.GetFloatData() != nil:
+		for _, v := range data.GetFloatData().GetData() {
+			values = append(values, v)
+		}
+	case data.GetDoubleData() != nil:
+		for _, v := range data.GetDoubleData().GetData() {
+			values = append(values, v)
+		}
+	case data.GetStringData() != nil:
+		for _, v := range data.GetStringData().GetData() {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,150 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowconv
+
+import (
+	stdjson "encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/util/importutilv2/json"
+	"github.com/milvus-io/milvus/pkg/common"
+)
+
+func decodeRows(t *testing.T, strs ...string) []any {
+	raws := make([]any, 0, len(strs))
+	for _, str := range strs {
+		dec := stdjson.NewDecoder(strings.NewReader(str))
+		dec.UseNumber()
+		var value any
+		assert.NoError(t, dec.Decode(&value))
+		raws = append(raws, value)
+	}
+	return raws
+}
+
+func TestParseToArrow(t *testing.T) {
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "id", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "vector", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}}},
+			{FieldID: 102, Name: "bin", DataType: schemapb.DataType_BinaryVector, TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "16"}}},
+			{FieldID: 103, Name: "name", DataType: schemapb.DataType_VarChar},
+			{FieldID: 104, Name: "flag", DataType: schemapb.DataType_Bool},
+			{FieldID: 105, Name: "level", DataType: schemapb.DataType_Int8},
+			{FieldID: 106, Name: "score", DataType: schemapb.DataType_Double},
+			{FieldID: 107, Name: "tags", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_Int32},
+			{FieldID: 108, Name: "attrs", DataType: schemapb.DataType_JSON},
+			{FieldID: 109, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+		},
+	}
+	arrowSchema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "vector", Type: arrow.FixedSizeListOf(2, arrow.PrimitiveTypes.Float32)},
+		{Name: "bin", Type: arrow.FixedSizeListOf(2, arrow.PrimitiveTypes.Uint8)},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "flag", Type: arrow.FixedWidthTypes.Boolean},
+		{Name: "level", Type: arrow.PrimitiveTypes.Int8},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "tags", Type: arrow.ListOf(arrow.PrimitiveTypes.Int32)},
+		{Name: "attrs", Type: arrow.BinaryTypes.Binary},
+		{Name: "$meta", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	parser, err := json.NewRowParser(schema)
+	assert.NoError(t, err)
+
+	raws := decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2], "bin": [1, 2], "name": "a", "flag": true, "level": 3, "score": 0.5, "tags": [1, 2], "attrs": {"a": 1}, "x": 1}`,
+		`{"id": 2, "vector": [0.3, 0.4], "bin": [3, 4], "name": "b", "flag": false, "level": -3, "score": 1.5, "tags": [], "attrs": {}}`,
+	)
+	record, err := ParseToArrow(parser, raws, arrowSchema)
+	assert.NoError(t, err)
+	defer record.Release()
+	assert.Equal(t, int64(2), record.NumRows())
+
+	// the values are the same as the map-based parse
+	rows, err := parser.ParseBatch(raws)
+	assert.NoError(t, err)
+	for i, row := range rows {
+		assert.Equal(t, row[100], record.Column(0).(*array.Int64).Value(i))
+		vectors := record.Column(1).(*array.FixedSizeList)
+		assert.Equal(t, row[101], vectors.ListValues().(*array.Float32).Float32Values()[i*2:i*2+2])
+		bins := record.Column(2).(*array.FixedSizeList)
+		assert.Equal(t, row[102], bins.ListValues().(*array.Uint8).Uint8Values()[i*2:i*2+2])
+		assert.Equal(t, row[103], record.Column(3).(*array.String).Value(i))
+		assert.Equal(t, row[104], record.Column(4).(*array.Boolean).Value(i))
+		assert.Equal(t, row[105], record.Column(5).(*array.Int8).Value(i))
+		assert.Equal(t, row[106], record.Column(6).(*array.Float64).Value(i))
+		tags := record.Column(7).(*array.List)
+		start, end := tags.ValueOffsets(i)
+		assert.Equal(t, row[107].(*schemapb.ScalarField).GetIntData().GetData(),
+			tags.ListValues().(*array.Int32).Int32Values()[start:end])
+		assert.Equal(t, row[108], record.Column(8).(*array.Binary).Value(i))
+	}
+	meta := record.Column(9).(*array.String)
+	assert.Equal(t, `{"x":1}`, meta.Value(0))
+	assert.Equal(t, `{}`, meta.Value(1))
+
+	// the type and the size of the arrow field must match the parsed value
+	_, err = ParseToArrow(parser, raws, arrow.NewSchema([]arrow.Field{
+		{Name: "vector", Type: arrow.FixedSizeListOf(3, arrow.PrimitiveTypes.Float32)},
+	}, nil))
+	assert.ErrorContains(t, err, "failed to parse row 0")
+	assert.ErrorContains(t, err, "failed to append the value of field 'vector'")
+	_, err = ParseToArrow(parser, raws, arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.PrimitiveTypes.Int64},
+	}, nil))
+	assert.ErrorContains(t, err, "cannot append the value of type 'string'")
+	_, err = ParseToArrow(parser, raws, arrow.NewSchema([]arrow.Field{
+		{Name: "unknown", Type: arrow.BinaryTypes.String},
+	}, nil))
+	assert.ErrorContains(t, err, "the field 'unknown' of the arrow schema is not found")
+	_, err = ParseToArrow(parser, decodeRows(t, `{"id": 1}`), arrowSchema)
+	assert.Error(t, err)
+
+	// the auto-generated primary key is missing from the rows
+	schema.Fields[0].AutoID = true
+	parser, err = json.NewRowParser(schema)
+	assert.NoError(t, err)
+	raws = decodeRows(t, `{"vector": [0.1, 0.2], "bin": [1, 2], "name": "a", "flag": true, "level": 3, "score": 0.5, "tags": [1], "attrs": {}}`)
+	record, err = ParseToArrow(parser, raws, arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+	}, nil))
+	assert.NoError(t, err)
+	defer record.Release()
+	assert.True(t, record.Column(0).IsNull(0))
+	assert.Equal(t, "a", record.Column(1).(*array.String).Value(0))
+	_, err = ParseToArrow(parser, raws, arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+	}, nil))
+	assert.ErrorContains(t, err, "failed to parse row 0")
+	assert.ErrorContains(t, err, "value of field 'id' is missed, but the arrow field is not nullable")
+
+	parser, err = json.NewRowParser(schema, json.WithDeleteMarker("_deleted"))
+	assert.NoError(t, err)
+	_, err = ParseToArrow(parser, decodeRows(t, `{"id": 1, "_deleted": true}`), arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	}, nil))
+	assert.ErrorContains(t, err, "the row is a deletion, which cannot be converted to arrow record")
+}
@@ -0,0 +1,327 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// ParseBatch parses the rows of a batch, it stops at the first invalid row.
+func (r *rowParser) ParseBatch(raws []any) ([]Row, error) {
+	if r.option.batchDimCheck {
+		if err := r.checkBatchDim(raws); err != nil {
+			return nil, err
+		}
+	}
+	if r.option.batchPKTypeCheck {
+		if err := r.checkBatchPKType(raws); err != nil {
+			return nil, err
+		}
+	}
+	rows := make([]Row, 0, len(raws))
+	var firstKeys typeutil.Set[string]
+	for i, raw := range raws {
+		row, err := r.Parse(raw)
+		if err != nil {
+			return nil, withRowIndex(err, i, merr.WrapErrImportFailed(fmt.Sprintf("failed to parse row %d, error: %v", i, err)))
+		}
+		if r.option.requireUniformSchema {
+			keys := r.fieldKeys(raw.(map[string]any))
+			if i == 0 {
+				firstKeys = keys
+			} else if err = checkUniformKeys(firstKeys, keys, i); err != nil {
+				return nil, err
+			}
+		}
+		rows = append(rows, row)
+	}
+	if r.option.emptyDynamicCheck && r.dynamicField != nil && len(rows) > 0 {
+		if err := r.checkEmptyDynamic(rows); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+// fieldKeys returns the keys of the row which match the fields, including the aliases and
+// the fields not projected, so the keys stored in the dynamic field are excluded.
+func (r *rowParser) fieldKeys(stringMap map[string]any) typeutil.Set[string] {
+	keys := typeutil.NewSet[string]()
+	for key := range stringMap {
+		if _, ok := r.name2FieldID[key]; ok {
+			keys.Insert(key)
+		} else if _, ok = r.aliasOf(key); ok {
+			keys.Insert(key)
+		} else if r.unprojected.Contain(key) {
+			keys.Insert(key)
+		}
+	}
+	return keys
+}
+
+// ParseAll parses the decoded top-level value of the input, which is either an array of rows
+// or a single row. The errors of an array report the index of the invalid element.
+func (r *rowParser) ParseAll(raw any) ([]Row, error) {
+	switch value := raw.(type) {
+	case []any:
+		return r.ParseBatch(value)
+	case map[string]any:
+		row, err := r.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		return []Row{row}, nil
+	default:
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("invalid JSON format, the input should be an array of rows or a single row, got %T", raw))
+	}
+}
+
+// checkUniformKeys reports the field keys which differ between the first row and the row.
+func checkUniformKeys(expected, actual typeutil.Set[string], rowIndex int) error {
+	missing := make([]string, 0)
+	for key := range expected {
+		if !actual.Contain(key) {
+			missing = append(missing, key)
+		}
+	}
+	unexpected := make([]string, 0)
+	for key := range actual {
+		if !expected.Contain(key) {
+			unexpected = append(unexpected, key)
+		}
+	}
+	if len(missing) == 0 && len(unexpected) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	sort.Strings(unexpected)
+	return merr.WrapErrImportFailed(fmt.Sprintf("row %d has different fields from row 0, missing %v, unexpected %v",
+		rowIndex, missing, unexpected))
+}
+
+// checkEmptyDynamic reports if none of the rows has a dynamic value, which is likely
+// caused by keys that were meant to be stored in the dynamic field but match nothing.
+func (r *rowParser) checkEmptyDynamic(rows []Row) error {
+	for _, row := range rows {
+		if value, ok := row[r.dynamicField.GetFieldID()].([]byte); ok && string(value) != "{}" {
+			return nil
+		}
+	}
+	if r.option.warnEmptyDynamic != nil {
+		r.option.warnEmptyDynamic(r.dynamicField.GetName())
+		return nil
+	}
+	return merr.WrapErrImportFailed(fmt.Sprintf("none of the %d rows in the batch has a value for the dynamic field '%s', "+
+		"please check whether the keys match the field names", len(rows), r.dynamicField.GetName()))
+}
+
+// observedDim returns the dim of a vector value as parseEntity reads it, i.e. the singleton
+// array is unwrapped, and the array converted from bytes or from floats to half-precision
+// is counted by its components. It returns false if the value is not an array.
+func (r *rowParser) observedDim(fieldID int64, value any) (int, bool) {
+	field := r.id2Field[fieldID]
+	if r.option.unwrapSingletonVector && typeutil.IsVectorType(field.GetDataType()) {
+		value = unwrapSingletonArray(value)
+	}
+	arr, ok := value.([]any)
+	if !ok {
+		return 0, false
+	}
+	dim := r.dims[fieldID]
+	switch field.GetDataType() {
+	case schemapb.DataType_BinaryVector:
+		return len(arr) * 8, true
+	case schemapb.DataType_FloatVector:
+		if r.option.floatVectorFromBytes && len(arr) == 4*dim {
+			return dim, true
+		}
+		return len(arr), true
+	case schemapb.DataType_Float16Vector, schemapb.DataType_BFloat16Vector:
+		if r.option.halfFloatFields.Contain(fieldID) && len(arr) == dim {
+			return dim, true
+		}
+		return len(arr) / 2, true
+	default:
+		return len(arr), true
+	}
+}
+
+// checkBatchPKType reports a single error if the primary keys of two or more rows in the batch
+// all fail to parse for the same unexpected JSON type, which means the schema and the data
+// don't match, rather than every row is invalid.
+func (r *rowParser) checkBatchPKType(raws []any) error {
+	if r.pkField.GetAutoID() {
+		return nil
+	}
+	provided := ""
+	count := 0
+	for _, raw := range raws {
+		stringMap, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		value, ok := r.lookupValue(stringMap, r.pkField.GetName())
+		if !ok || value == nil {
+			continue
+		}
+		if r.pkTypeMatches(value) {
+			return nil
+		}
+		name := jsonTypeName(value)
+		if count > 0 && name != provided {
+			return nil
+		}
+		provided = name
+		count++
+	}
+	if count < 2 {
+		return nil
+	}
+	name := r.pkField.GetName()
+	hint := fmt.Sprintf("please check the type of the primary key '%s' in the collection schema", name)
+	switch {
+	case r.pkField.GetDataType() == schemapb.DataType_Int64 && provided == "string":
+		hint = fmt.Sprintf("please declare the primary key '%s' as VarChar, or provide the primary keys as integers", name)
+	case r.pkField.GetDataType() == schemapb.DataType_VarChar && provided == "number":
+		hint = fmt.Sprintf("please declare the primary key '%s' as Int64, or provide the primary keys as strings", name)
+	}
+	return merr.WrapErrImportFailed(fmt.Sprintf("PK type mismatch: schema expects %s, data provides %s in all %d rows of the batch, %s",
+		r.pkField.GetDataType().String(), provided, count, hint))
+}
+
+// pkTypeMatches returns whether the value has a JSON type which the primary key accepts,
+// without parsing it, so no callback of the parsing is triggered by the check.
+func (r *rowParser) pkTypeMatches(value any) bool {
+	fieldID := r.pkField.GetFieldID()
+	if _, ok := r.option.timestampFields[fieldID]; ok {
+		return true
+	}
+	if _, ok := r.option.scaleFields[fieldID]; ok {
+		return true
+	}
+	if r.pkField.GetDataType() != schemapb.DataType_Int64 {
+		_, ok := value.(string)
+		return ok
+	}
+	switch value.(type) {
+	case bool:
+		return r.option.boolAsInteger
+	case string:
+		if r.option.hexStringPK {
+			return true
+		}
+	}
+	_, ok := r.asNumber(value)
+	return ok
+}
+
+// jsonTypeName returns the JSON type of the decoded value.
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case nil:
+		return "null"
+	default:
+		return "number"
+	}
+}
+
+// hasPK returns whether the row provides the primary key, by its name or one of its aliases.
+func (r *rowParser) hasPK(stringMap map[string]any) bool {
+	_, ok := r.lookupValue(stringMap, r.pkField.GetName())
+	return ok
+}
+
+// CheckAutoID reports the mismatch between the schema and the file, which is found by the
+// sampled rows: the rows provide the primary key while it's auto-generated, or none of the
+// rows provides it while it's not. The deletion rows, which always provide the primary key,
+// are not counted, and the rows missing the primary key partially are left to Parse.
+func (r *rowParser) CheckAutoID(samples []any) error {
+	name := r.pkField.GetName()
+	total, provided := 0, 0
+	for _, raw := range samples {
+		stringMap, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		if deleted, _ := stringMap[r.option.deleteMarker].(bool); deleted && r.option.deleteMarker != "" {
+			continue
+		}
+		total++
+		if r.hasPK(stringMap) {
+			provided++
+		}
+	}
+	if r.pkField.GetAutoID() && provided > 0 {
+		return merr.WrapErrImportFailed(fmt.Sprintf("the primary key '%s' is auto-generated, but %d of %d sampled rows provide it, "+
+			"the file seems to be exported from a collection without autoID", name, provided, total))
+	}
+	if !r.pkField.GetAutoID() && !r.isComputedField(r.pkField.GetFieldID()) && total > 0 && provided == 0 {
+		return merr.WrapErrImportFailed(fmt.Sprintf("the primary key '%s' is not auto-generated, but none of %d sampled rows provides it, "+
+			"the file seems to be exported from a collection with autoID", name, total))
+	}
+	return nil
+}
+
+// checkBatchDim reports a single error if all the vectors of a field in the batch
+// have the same dim which differs from the schema, which means the schema and
+// the data don't match, rather than the rows are invalid.
+func (r *rowParser) checkBatchDim(raws []any) error {
+	for _, fieldID := range r.vectorFields {
+		dim, ok := r.dims[fieldID]
+		if !ok {
+			continue
+		}
+		field := r.id2Field[fieldID]
+		observed, count := -1, 0
+		for _, raw := range raws {
+			stringMap, ok := raw.(map[string]any)
+			if !ok {
+				continue
+			}
+			value, _ := r.lookupValue(stringMap, field.GetName())
+			d, ok := r.observedDim(fieldID, value)
+			if !ok {
+				continue
+			}
+			if observed != -1 && d != observed {
+				observed = -1
+				break
+			}
+			observed = d
+			count++
+		}
+		if count > 0 && observed != -1 && observed != dim {
+			return merr.WrapErrImportFailed(fmt.Sprintf("schema and data dimension mismatch for field '%s': "+
+				"the schema declares dim %d but all %d rows in the batch have dim %d, please check the collection schema",
+				field.GetName(), dim, count, observed))
+		}
+	}
+	return nil
+}
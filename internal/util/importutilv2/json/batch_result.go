@@ -0,0 +1,153 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// BatchResult is the summary of a batch parsed by ParseBatchWithResult.
+type BatchResult struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	// Failures are the errors of the failed rows grouped by kind, the errors
+	// which are not ParseError are of kind ErrKindOther.
+	Failures map[ParseErrorKind][]*ParseError `json:"failures"`
+	// Defaulted is the number of values filled by the parser rather than given by the rows per field,
+	// i.e. the values of the computed fields, and the empty object of the dynamic field for the rows
+	// which give neither the dynamic field nor any key stored in it.
+	Defaulted map[string]int `json:"defaulted"`
+	// DynamicRows is the number of succeeded rows which have any key stored in the dynamic field,
+	// DynamicKeys and DynamicBytes are the total keys and the total bytes of the dynamic field.
+	DynamicRows  int `json:"dynamic_rows"`
+	DynamicKeys  int `json:"dynamic_keys"`
+	DynamicBytes int `json:"dynamic_bytes"`
+	// Omitted is the number of the errors not collected in Failures due to WithMaxCollectedErrors,
+	// Failed still counts all of them.
+	Omitted int `json:"omitted"`
+
+	maxErrors int
+}
+
+func newBatchResult(total int, maxErrors int) *BatchResult {
+	return &BatchResult{
+		Total:     total,
+		Failures:  make(map[ParseErrorKind][]*ParseError),
+		Defaulted: make(map[string]int),
+		maxErrors: maxErrors,
+	}
+}
+
+func (b *BatchResult) addFailure(err error, rowIndex int) {
+	b.Failed++
+	if b.maxErrors > 0 && b.Failed > b.maxErrors {
+		b.Omitted++
+		return
+	}
+	parseErr := &ParseError{}
+	if errors.As(withRowIndex(err, rowIndex, err), &parseErr) {
+		b.Failures[parseErr.Kind] = append(b.Failures[parseErr.Kind], parseErr)
+		return
+	}
+	b.Failures[ErrKindOther] = append(b.Failures[ErrKindOther], &ParseError{RowIndex: rowIndex, Kind: ErrKindOther, err: err})
+}
+
+// ParseBatchWithResult parses all the rows of a batch, unlike ParseBatch it doesn't stop at
+// the invalid rows, it returns the valid rows and the summary of the batch. The error is
+// only returned by the checks of the whole batch.
+func (r *rowParser) ParseBatchWithResult(raws []any) ([]Row, *BatchResult, error) {
+	if r.option.batchDimCheck {
+		if err := r.checkBatchDim(raws); err != nil {
+			return nil, nil, err
+		}
+	}
+	if r.option.batchPKTypeCheck {
+		if err := r.checkBatchPKType(raws); err != nil {
+			return nil, nil, err
+		}
+	}
+	result := newBatchResult(len(raws), r.option.maxCollectedErrors)
+	rows := make([]Row, 0, len(raws))
+	var firstKeys typeutil.Set[string]
+	for i, raw := range raws {
+		// the rows are compared with row 0 even if it's invalid
+		if stringMap, ok := raw.(map[string]any); ok && r.option.requireUniformSchema && i == 0 {
+			firstKeys = r.fieldKeys(stringMap)
+		}
+		var dynamicKeys []string
+		row, err := r.parse(raw, &dynamicKeys)
+		if err != nil {
+			result.addFailure(err, i)
+			continue
+		}
+		if firstKeys != nil && i > 0 {
+			if err = checkUniformKeys(firstKeys, r.fieldKeys(raw.(map[string]any)), i); err != nil {
+				result.addFailure(newParseError(ErrKindInvalidValue, nil, nil, err), i)
+				continue
+			}
+		}
+		result.Succeeded++
+		rows = append(rows, row)
+		if IsDeleted(row) {
+			continue
+		}
+		for _, computed := range r.option.computedFields {
+			result.Defaulted[r.id2Field[computed.fieldID].GetName()]++
+		}
+		if r.dynamicField != nil && len(dynamicKeys) == 0 {
+			if stringMap, ok := raw.(map[string]any); ok {
+				if _, ok = r.lookupValue(stringMap, r.dynamicField.GetName()); !ok {
+					result.Defaulted[r.dynamicField.GetName()]++
+				}
+			}
+		}
+		if len(dynamicKeys) > 0 {
+			result.DynamicRows++
+			result.DynamicKeys += len(dynamicKeys)
+		}
+		if r.dynamicField != nil {
+			if value, ok := row[r.dynamicField.GetFieldID()].([]byte); ok {
+				result.DynamicBytes += len(value)
+			}
+		}
+	}
+	if r.option.emptyDynamicCheck && r.dynamicField != nil && len(rows) > 0 {
+		if err := r.checkEmptyDynamic(rows); err != nil {
+			return nil, nil, err
+		}
+	}
+	return rows, result, nil
+}
+
+// String returns the summary for logs.
+func (b *BatchResult) String() string {
+	failures := make(map[ParseErrorKind]int, len(b.Failures))
+	for kind, errs := range b.Failures {
+		failures[kind] = len(errs)
+	}
+	omitted := ""
+	if b.Omitted > 0 {
+		omitted = fmt.Sprintf(" and %d more", b.Omitted)
+	}
+	return fmt.Sprintf("total: %d, succeeded: %d, failed: %d, failures: %v%s, defaulted: %v, dynamic rows: %d, dynamic keys: %d, dynamic bytes: %d",
+		b.Total, b.Succeeded, b.Failed, failures, omitted, b.Defaulted, b.DynamicRows, b.DynamicKeys, b.DynamicBytes)
+}
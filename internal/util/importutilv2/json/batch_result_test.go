@@ -0,0 +1,130 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestRowParser_ParseBatchWithResult(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "name", DataType: schemapb.DataType_VarChar},
+		&schemapb.FieldSchema{FieldID: 103, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+	)
+	parser, err := NewRowParser(schema, WithComputedField(102, func(row Row) (any, error) {
+		return "computed", nil
+	}))
+	assert.NoError(t, err)
+
+	rows, result, err := parser.ParseBatchWithResult(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2], "a": 1, "b": 2}`,
+		`{"id": 2, "vector": [0.1]}`,
+		`{"id": 3, "vector": [0.1, 0.2]}`,
+		`{"id": "x", "vector": [0.1, 0.2]}`,
+		`{"id": 5}`,
+		`{"id": 6, "vector": [0.1, 0.2], "c": "d"}`,
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(rows))
+	assert.Equal(t, int64(6), rows[2][100])
+	assert.Equal(t, 6, result.Total)
+	assert.Equal(t, 3, result.Succeeded)
+	assert.Equal(t, 3, result.Failed)
+	assert.Equal(t, 1, len(result.Failures[ErrKindDimMismatch]))
+	assert.Equal(t, 1, result.Failures[ErrKindDimMismatch][0].RowIndex)
+	assert.Equal(t, 1, len(result.Failures[ErrKindMissingField]))
+	assert.Equal(t, 4, result.Failures[ErrKindMissingField][0].RowIndex)
+	assert.Equal(t, 1, len(result.Failures[ErrKindTypeMismatch]))
+	assert.Equal(t, 3, result.Failures[ErrKindTypeMismatch][0].RowIndex)
+	assert.Equal(t, map[string]int{"name": 3, "$meta": 1}, result.Defaulted)
+	assert.Equal(t, 2, result.DynamicRows)
+	assert.Equal(t, 3, result.DynamicKeys)
+	assert.Equal(t, len(`{"a":1,"b":2}`)+len(`{}`)+len(`{"c":"d"}`), result.DynamicBytes)
+	assert.Contains(t, result.String(), "total: 6, succeeded: 3, failed: 3")
+
+	bytes, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.Contains(t, string(bytes), `"row_index":1`)
+
+	// the dynamic field given by the row is not defaulted
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	_, result, err = parser.ParseBatchWithResult(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2], "name": "a", "$meta": {}}`,
+		`{"id": 2, "vector": [0.1, 0.2], "name": "b"}`,
+		`{"id": 3, "vector": [0.1, 0.2], "name": "c", "a": 1}`,
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"$meta": 1}, result.Defaulted)
+
+	// the batch checks still fail the whole batch
+	parser, err = NewRowParser(schema, WithBatchDimCheck())
+	assert.NoError(t, err)
+	_, _, err = parser.ParseBatchWithResult(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2, 0.3], "name": "a"}`,
+		`{"id": 2, "vector": [0.1, 0.2, 0.3], "name": "b"}`,
+	))
+	assert.ErrorContains(t, err, "schema and data dimension mismatch")
+
+	// a row which differs from row 0 fails when the uniform schema is required
+	parser, err = NewRowParser(schema, WithRequireUniformSchema(), WithAliases(map[string][]string{"vector": {"vec"}}))
+	assert.NoError(t, err)
+	_, result, err = parser.ParseBatchWithResult(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2], "name": "a"}`,
+		`{"id": 2, "vec": [0.1, 0.2], "name": "b"}`,
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 1, len(result.Failures[ErrKindInvalidValue]))
+	assert.Equal(t, 1, result.Failures[ErrKindInvalidValue][0].RowIndex)
+}
+
+func TestRowParser_MaxCollectedErrors(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema(), WithMaxCollectedErrors(2))
+	assert.NoError(t, err)
+
+	raws := decodeRows(t,
+		`{"id": 1, "vector": [0.1]}`,
+		`{"id": 2}`,
+		`{"id": 3, "vector": [0.1, 0.2]}`,
+		`{"id": "x", "vector": [0.1, 0.2]}`,
+		`{"id": 5, "vector": [0.1]}`,
+		`{"id": 6, "vector": [0.1]}`,
+	)
+	rows, result, err := parser.ParseBatchWithResult(raws)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, 5, result.Failed)
+	assert.Equal(t, 3, result.Omitted)
+	assert.Equal(t, 1, len(result.Failures[ErrKindDimMismatch]))
+	assert.Equal(t, 1, len(result.Failures[ErrKindMissingField]))
+	assert.Equal(t, 0, len(result.Failures[ErrKindTypeMismatch]))
+	assert.Contains(t, result.String(), "failed: 5, failures: map[dim_mismatch:1 missing_field:1] and 3 more")
+
+	// all the errors are collected by default
+	parser, err = NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	_, result, err = parser.ParseBatchWithResult(raws)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Omitted)
+	assert.Equal(t, 3, len(result.Failures[ErrKindDimMismatch]))
+}
@@ -0,0 +1,325 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+)
+
+func decodeRows(t *testing.T, strs ...string) []any {
+	raws := make([]any, 0, len(strs))
+	for _, str := range strs {
+		raws = append(raws, decodeRow(t, str))
+	}
+	return raws
+}
+
+func TestRowParser_ParseBatch(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+
+	rows, err := parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2]}`,
+		`{"id": 2, "vector": [0.3, 0.4]}`,
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, int64(2), rows[1][100])
+
+	_, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2]}`,
+		`{"id": 2, "vector": [0.3]}`,
+	))
+	assert.ErrorContains(t, err, "failed to parse row 1")
+}
+
+func TestRowParser_ParseAll(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+
+	rows, err := parser.ParseAll(decodeRow(t, `[{"id": 1, "vector": [0.1, 0.2]}, {"id": 2, "vector": [0.3, 0.4]}]`))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, int64(2), rows[1][100])
+
+	rows, err = parser.ParseAll(decodeRow(t, `{"id": 3, "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, int64(3), rows[0][100])
+
+	rows, err = parser.ParseAll(decodeRow(t, `[]`))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(rows))
+
+	_, err = parser.ParseAll(decodeRow(t, `[{"id": 1, "vector": [0.1, 0.2]}, 2]`))
+	assert.ErrorContains(t, err, "failed to parse row 1")
+	_, err = parser.ParseAll(decodeRow(t, `"row"`))
+	assert.ErrorContains(t, err, "the input should be an array of rows or a single row")
+}
+
+func TestRowParser_CheckAutoID(t *testing.T) {
+	withPK := decodeRows(t, `{"id": 1, "vector": [0.1, 0.2]}`, `{"id": 2, "vector": [0.3, 0.4]}`)
+	withoutPK := decodeRows(t, `{"vector": [0.1, 0.2]}`, `{"vector": [0.3, 0.4]}`)
+
+	parser, err := NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	assert.NoError(t, parser.CheckAutoID(withPK))
+	assert.NoError(t, parser.CheckAutoID(nil))
+	// the rows missing the primary key partially are reported by Parse
+	assert.NoError(t, parser.CheckAutoID([]any{withoutPK[0], withPK[0]}))
+	err = parser.CheckAutoID(withoutPK)
+	assert.ErrorContains(t, err, "the primary key 'id' is not auto-generated, but none of 2 sampled rows provides it")
+
+	schema := newTestSchema()
+	schema.Fields[0].AutoID = true
+	parser, err = NewRowParser(schema, WithDeleteMarker("_deleted"))
+	assert.NoError(t, err)
+	assert.NoError(t, parser.CheckAutoID(decodeRows(t, `{"vector": [0.1, 0.2]}`, `{"id": 1, "_deleted": true}`)))
+	err = parser.CheckAutoID(withPK)
+	assert.ErrorContains(t, err, "the primary key 'id' is auto-generated, but 2 of 2 sampled rows provide it")
+
+	// the primary key provided by an alias or a normalized name is detected
+	schema = newTestSchema()
+	schema.Fields[0].Name = "pk_id"
+	parser, err = NewRowParser(schema, WithAliases(map[string][]string{"pk_id": {"key"}}), WithNameNormalization(NameNormalizationSnakeCamel))
+	assert.NoError(t, err)
+	assert.NoError(t, parser.CheckAutoID(decodeRows(t, `{"key": 1, "vector": [0.1, 0.2]}`, `{"pkId": 2, "vector": [0.3, 0.4]}`)))
+	schema.Fields[0].AutoID = true
+	parser, err = NewRowParser(schema, WithNameNormalization(NameNormalizationSnakeCamel))
+	assert.NoError(t, err)
+	err = parser.CheckAutoID(decodeRows(t, `{"vector": [0.1, 0.2]}`, `{"pkId": 2, "vector": [0.3, 0.4]}`))
+	assert.ErrorContains(t, err, "the primary key 'pk_id' is auto-generated, but 1 of 2 sampled rows provide it")
+	_, err = parser.Parse(decodeRow(t, `{"pkId": 2, "vector": [0.3, 0.4]}`))
+	assert.ErrorContains(t, err, "the primary key 'pk_id' is auto-generated, no need to provide")
+}
+
+func TestRowParser_BatchDimCheck(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema(), WithBatchDimCheck())
+	assert.NoError(t, err)
+
+	_, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2, 0.3]}`,
+		`{"id": 2, "vector": [0.4, 0.5, 0.6]}`,
+		`{"id": 3, "vector": [0.7, 0.8, 0.9]}`,
+	))
+	assert.ErrorContains(t, err, "schema and data dimension mismatch for field 'vector'")
+	assert.ErrorContains(t, err, "the schema declares dim 2 but all 3 rows in the batch have dim 3")
+
+	// inconsistent dims are reported per row
+	_, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2]}`,
+		`{"id": 2, "vector": [0.4, 0.5, 0.6]}`,
+	))
+	assert.ErrorContains(t, err, "failed to parse row 1")
+	assert.ErrorContains(t, err, "expected dim '2'")
+
+	rows, err := parser.ParseBatch(decodeRows(t, `{"id": 1, "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(rows))
+
+	// the vectors provided by aliases or normalized names are checked too
+	for _, opt := range []RowParserOption{
+		WithAliases(map[string][]string{"dense_vector": {"denseVector"}}),
+		WithNameNormalization(NameNormalizationSnakeCamel),
+	} {
+		schema := newTestSchema()
+		schema.Fields[1].Name = "dense_vector"
+		parser, err = NewRowParser(schema, WithBatchDimCheck(), opt)
+		assert.NoError(t, err)
+		_, err = parser.ParseBatch(decodeRows(t,
+			`{"id": 1, "denseVector": [0.1, 0.2, 0.3]}`,
+			`{"id": 2, "denseVector": [0.4, 0.5, 0.6]}`,
+		))
+		assert.ErrorContains(t, err, "the schema declares dim 2 but all 2 rows in the batch have dim 3")
+	}
+
+	// the dim is observed as the parser reads the vector
+	parser, err = NewRowParser(newTestSchema(), WithBatchDimCheck(), WithFloatVectorFromBytes(binary.LittleEndian))
+	assert.NoError(t, err)
+	rows, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0, 0, 0, 0, 0, 0, 0, 0]}`,
+		`{"id": 2, "vector": [0, 0, 0, 0, 0, 0, 0, 0]}`,
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+
+	parser, err = NewRowParser(newTestSchema(), WithBatchDimCheck(), WithUnwrapSingletonVectorArray())
+	assert.NoError(t, err)
+	rows, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [[0.1, 0.2]]}`,
+		`{"id": 2, "vector": [[0.3, 0.4]]}`,
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+	_, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [[0.1, 0.2, 0.3]]}`,
+		`{"id": 2, "vector": [[0.4, 0.5, 0.6]]}`,
+	))
+	assert.ErrorContains(t, err, "the schema declares dim 2 but all 2 rows in the batch have dim 3")
+
+	schema := newTestSchema(&schemapb.FieldSchema{
+		FieldID:    102,
+		Name:       "fp16",
+		DataType:   schemapb.DataType_Float16Vector,
+		TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}},
+	})
+	parser, err = NewRowParser(schema, WithBatchDimCheck(), WithHalfFloatConversion(102))
+	assert.NoError(t, err)
+	rows, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2], "fp16": [1, -2]}`,
+		`{"id": 2, "vector": [0.1, 0.2], "fp16": [0, 60, 0, 192]}`,
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+
+	// the first vector field of the schema is reported
+	parser, err = NewRowParser(newTestSchema(&schemapb.FieldSchema{
+		FieldID:    102,
+		Name:       "vector2",
+		DataType:   schemapb.DataType_FloatVector,
+		TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}},
+	}), WithBatchDimCheck())
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		_, err = parser.ParseBatch(decodeRows(t,
+			`{"id": 1, "vector": [0.1, 0.2, 0.3], "vector2": [0.1, 0.2, 0.3]}`,
+			`{"id": 2, "vector": [0.4, 0.5, 0.6], "vector2": [0.4, 0.5, 0.6]}`,
+		))
+		assert.ErrorContains(t, err, "schema and data dimension mismatch for field 'vector':")
+	}
+}
+
+func TestRowParser_BatchPKTypeCheck(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema(), WithBatchPKTypeCheck())
+	assert.NoError(t, err)
+
+	raws := decodeRows(t,
+		`{"id": "a", "vector": [0.1, 0.2]}`,
+		`{"id": "b", "vector": [0.3, 0.4]}`,
+	)
+	_, err = parser.ParseBatch(raws)
+	assert.ErrorContains(t, err, "PK type mismatch: schema expects Int64, data provides string in all 2 rows of the batch, "+
+		"please declare the primary key 'id' as VarChar")
+	_, _, err = parser.ParseBatchWithResult(raws)
+	assert.ErrorContains(t, err, "PK type mismatch")
+
+	// a single row or the mixed types are reported row by row
+	_, err = parser.ParseBatch(raws[:1])
+	assert.ErrorContains(t, err, "failed to parse row 0")
+	_, err = parser.ParseBatch(decodeRows(t,
+		`{"id": "a", "vector": [0.1, 0.2]}`,
+		`{"id": 2, "vector": [0.3, 0.4]}`,
+	))
+	assert.ErrorContains(t, err, "failed to parse row 0")
+
+	schema := newTestSchema()
+	schema.Fields[0].DataType = schemapb.DataType_VarChar
+	schema.Fields[0].TypeParams = []*commonpb.KeyValuePair{{Key: common.MaxLengthKey, Value: "8"}}
+	parser, err = NewRowParser(schema, WithBatchPKTypeCheck())
+	assert.NoError(t, err)
+	_, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2]}`,
+		`{"id": 2, "vector": [0.3, 0.4]}`,
+	))
+	assert.ErrorContains(t, err, "PK type mismatch: schema expects VarChar, data provides number in all 2 rows of the batch, "+
+		"please declare the primary key 'id' as Int64")
+
+	// the check doesn't parse the primary keys, so the parsing is observed once per row
+	core, logs := observer.New(zapcore.DebugLevel)
+	parser, err = NewRowParser(newTestSchema(), WithBatchPKTypeCheck(), WithBoolAsInteger(), WithLogger(zap.New(core)))
+	assert.NoError(t, err)
+	rows, err := parser.ParseBatch(decodeRows(t,
+		`{"id": true, "vector": [0.1, 0.2]}`,
+		`{"id": 2, "vector": [0.3, 0.4]}`,
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), rows[0][100])
+	assert.Equal(t, 1, logs.FilterMessage("coerce bool to integer").Len())
+}
+
+func TestRowParser_EmptyDynamicFieldCheck(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+	)
+	parser, err := NewRowParser(schema, WithEmptyDynamicFieldCheck(nil))
+	assert.NoError(t, err)
+	_, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2]}`,
+		`{"id": 2, "vector": [0.1, 0.2]}`,
+	))
+	assert.ErrorContains(t, err, "none of the 2 rows in the batch has a value for the dynamic field '$meta'")
+
+	rows, err := parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2]}`,
+		`{"id": 2, "vector": [0.1, 0.2], "a": 1}`,
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+
+	warned := ""
+	parser, err = NewRowParser(schema, WithEmptyDynamicFieldCheck(func(fieldName string) {
+		warned = fieldName
+	}))
+	assert.NoError(t, err)
+	_, err = parser.ParseBatch(decodeRows(t, `{"id": 1, "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "$meta", warned)
+}
+
+func TestRowParser_RequireUniformSchema(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "name", DataType: schemapb.DataType_VarChar},
+		&schemapb.FieldSchema{FieldID: 103, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+	)
+	parser, err := NewRowParser(schema, WithRequireUniformSchema(), WithProjectFields(100, 101),
+		WithAliases(map[string][]string{"vector": {"vec"}}))
+	assert.NoError(t, err)
+
+	// the dynamic keys are not compared
+	rows, err := parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2], "name": "a", "x": 1}`,
+		`{"id": 2, "vector": [0.3, 0.4], "name": "b", "y": 2}`,
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+
+	_, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2], "name": "a"}`,
+		`{"id": 2, "vector": [0.3, 0.4], "name": "b"}`,
+		`{"id": 3, "vec": [0.5, 0.6]}`,
+	))
+	assert.ErrorContains(t, err, "row 2 has different fields from row 0, missing [name vector], unexpected [vec]")
+
+	// not checked by default
+	parser, err = NewRowParser(schema, WithProjectFields(100, 101))
+	assert.NoError(t, err)
+	_, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2], "name": "a"}`,
+		`{"id": 2, "vector": [0.3, 0.4]}`,
+	))
+	assert.NoError(t, err)
+}
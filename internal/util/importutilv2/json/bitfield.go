@@ -0,0 +1,78 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// BitOrder is the order in which the bits of a bitfield are unpacked into the array.
+type BitOrder int
+
+const (
+	// BitOrderLSBFirst maps the least significant bit to the first element.
+	BitOrderLSBFirst BitOrder = iota
+	// BitOrderMSBFirst maps the most significant bit of the length to the first element.
+	BitOrderMSBFirst
+)
+
+type bitfieldOption struct {
+	length int
+	order  BitOrder
+}
+
+func (r *rowParser) checkBitfields() error {
+	for fieldID, bitfield := range r.option.bitfields {
+		field := r.id2Field[fieldID]
+		if field.GetDataType() != schemapb.DataType_Array || field.GetElementType() != schemapb.DataType_Bool {
+			return merr.WrapErrImportFailed(fmt.Sprintf("bitfield is only supported for Array<Bool> field, field id: %d", fieldID))
+		}
+		if bitfield.length <= 0 || bitfield.length > 64 {
+			return merr.WrapErrImportFailed(fmt.Sprintf("invalid bitfield length %d of field '%s', it should be in [1, 64]",
+				bitfield.length, field.GetName()))
+		}
+	}
+	return nil
+}
+
+// unpackBitfield unpacks the integer into the booleans of the bitfield length.
+func (r *rowParser) unpackBitfield(num string, fieldID int64) ([]any, error) {
+	bitfield := r.option.bitfields[fieldID]
+	name := r.id2Field[fieldID].GetName()
+	bits, err := strconv.ParseUint(num, 10, 64)
+	if err != nil {
+		return nil, newParseError(ErrKindInvalidValue, r.id2Field[fieldID], num, merr.WrapErrImportFailed(
+			fmt.Sprintf("invalid bitfield '%s' of field '%s', it should be a non-negative integer", num, name)))
+	}
+	if bitfield.length < 64 && bits>>bitfield.length != 0 {
+		return nil, newParseError(ErrKindInvalidValue, r.id2Field[fieldID], num, merr.WrapErrImportFailed(
+			fmt.Sprintf("bitfield '%s' of field '%s' has bits set beyond the length %d", num, name, bitfield.length)))
+	}
+	arr := make([]any, bitfield.length)
+	for i := range arr {
+		bit := i
+		if bitfield.order == BitOrderMSBFirst {
+			bit = bitfield.length - 1 - i
+		}
+		arr[i] = bits&(1<<bit) != 0
+	}
+	return arr, nil
+}
@@ -0,0 +1,76 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestRowParser_BoolBitfield(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "flags", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_Bool})
+	parse := func(parser RowParser, flags string) ([]bool, error) {
+		row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "flags": `+flags+`}`))
+		if err != nil {
+			return nil, err
+		}
+		return row[102].(*schemapb.ScalarField).GetBoolData().GetData(), nil
+	}
+
+	parser, err := NewRowParser(schema, WithBoolBitfield(102, 4, BitOrderLSBFirst))
+	assert.NoError(t, err)
+	flags, err := parse(parser, "5")
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false, true, false}, flags)
+	flags, err = parse(parser, "0")
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, false, false, false}, flags)
+	// the arrays are still accepted
+	flags, err = parse(parser, "[true]")
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true}, flags)
+
+	_, err = parse(parser, "16")
+	assert.ErrorContains(t, err, "bitfield '16' of field 'flags' has bits set beyond the length 4")
+	_, err = parse(parser, "-1")
+	assert.ErrorContains(t, err, "invalid bitfield '-1' of field 'flags'")
+	_, err = parse(parser, "1.5")
+	assert.ErrorContains(t, err, "invalid bitfield '1.5' of field 'flags'")
+
+	parser, err = NewRowParser(schema, WithBoolBitfield(102, 4, BitOrderMSBFirst))
+	assert.NoError(t, err)
+	flags, err = parse(parser, "5")
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, true, false, true}, flags)
+	flags, err = parse(parser, "8")
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false, false, false}, flags)
+
+	parser, err = NewRowParser(schema, WithBoolBitfield(102, 64, BitOrderMSBFirst))
+	assert.NoError(t, err)
+	flags, err = parse(parser, "18446744073709551615")
+	assert.NoError(t, err)
+	assert.Equal(t, 64, len(flags))
+
+	_, err = NewRowParser(schema, WithBoolBitfield(102, 65, BitOrderLSBFirst))
+	assert.ErrorContains(t, err, "invalid bitfield length 65 of field 'flags'")
+	_, err = NewRowParser(schema, WithBoolBitfield(100, 4, BitOrderLSBFirst))
+	assert.ErrorContains(t, err, "bitfield is only supported for Array<Bool> field")
+}
@@ -0,0 +1,206 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+// canonicalizeJSONValue normalizes the numbers in a decoded JSON value, so that
+// "1", "1.0" and "1e0" are all written as 1 when the value is marshaled.
+// Keys need no treatment since json.Marshal always sorts the keys of maps.
+func canonicalizeJSONValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(v))
+		for key, elem := range v {
+			res[key] = canonicalizeJSONValue(elem)
+		}
+		return res
+	case []any:
+		res := make([]any, len(v))
+		for i, elem := range v {
+			res[i] = canonicalizeJSONValue(elem)
+		}
+		return res
+	case json.Number:
+		return canonicalizeNumber(v)
+	default:
+		return value
+	}
+}
+
+// normalizeJSONValue prepares a value decoded by a decoder other than encoding/json, such as
+// a BSON or YAML decoder, to be marshaled. The native integers and floats are marshaled losslessly
+// as they are, the maps with non-string keys which are produced by some YAML decoders are converted,
+// and the non-finite floats, which have no JSON form, are rejected with the path to them.
+func normalizeJSONValue(value any, path string) (any, error) {
+	convert, err := checkJSONValue(value, path)
+	if err != nil || !convert {
+		return value, err
+	}
+	return convertJSONValue(value), nil
+}
+
+// checkJSONValue rejects the values which cannot be marshaled, and reports whether any map
+// needs to be converted, so that the common case is not copied.
+func checkJSONValue(value any, path string) (bool, error) {
+	convert := false
+	switch v := value.(type) {
+	case map[string]any:
+		for key, elem := range v {
+			c, err := checkJSONValue(elem, path+"."+key)
+			if err != nil {
+				return false, err
+			}
+			convert = convert || c
+		}
+	case map[any]any:
+		for key, elem := range v {
+			name, ok := key.(string)
+			if !ok {
+				return false, fmt.Errorf("the key '%v' at '%s' is not a string", key, path)
+			}
+			if _, err := checkJSONValue(elem, path+"."+name); err != nil {
+				return false, err
+			}
+		}
+		convert = true
+	case []any:
+		for i, elem := range v {
+			c, err := checkJSONValue(elem, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return false, err
+			}
+			convert = convert || c
+		}
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false, fmt.Errorf("the value '%v' at '%s' is not representable in JSON", v, path)
+		}
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return false, fmt.Errorf("the value '%v' at '%s' is not representable in JSON", v, path)
+		}
+	}
+	return convert, nil
+}
+
+// convertJSONValue converts the maps with string keys checked by checkJSONValue to map[string]any.
+func convertJSONValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(v))
+		for key, elem := range v {
+			res[key] = convertJSONValue(elem)
+		}
+		return res
+	case map[any]any:
+		res := make(map[string]any, len(v))
+		for key, elem := range v {
+			res[key.(string)] = convertJSONValue(elem)
+		}
+		return res
+	case []any:
+		res := make([]any, len(v))
+		for i, elem := range v {
+			res[i] = convertJSONValue(elem)
+		}
+		return res
+	default:
+		return value
+	}
+}
+
+// canonicalizeJSONString returns the canonical bytes of a JSON-encoded string.
+func canonicalizeJSONString(str string) ([]byte, error) {
+	dec := json.NewDecoder(strings.NewReader(str))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid character after top-level value in '%s'", str)
+	}
+	return json.Marshal(canonicalizeJSONValue(value))
+}
+
+// marshalOrdered marshals the map with the keys in the given order first, then the other
+// keys in alphabetical order. The nested maps are marshaled with sorted keys as usual.
+func marshalOrdered(values map[string]any, order []string) ([]byte, error) {
+	keys := make([]string, 0, len(values))
+	ordered := typeutil.NewSet[string]()
+	for _, key := range order {
+		if _, ok := values[key]; ok && !ordered.Contain(key) {
+			keys = append(keys, key)
+			ordered.Insert(key)
+		}
+	}
+	rest := make([]string, 0, len(values)-len(keys))
+	for key := range values {
+		if !ordered.Contain(key) {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	keys = append(keys, rest...)
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func canonicalizeNumber(num json.Number) json.Number {
+	if i, err := strconv.ParseInt(num.String(), 10, 64); err == nil {
+		return json.Number(strconv.FormatInt(i, 10))
+	}
+	f, err := strconv.ParseFloat(num.String(), 64)
+	if err != nil {
+		return num
+	}
+	if f == float64(int64(f)) && f >= -(1<<53) && f <= 1<<53 {
+		return json.Number(strconv.FormatInt(int64(f), 10))
+	}
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+}
@@ -0,0 +1,198 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"fmt"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// ColumnSink receives the parsed values of the rows field by field, so the values can be
+// written into a columnar output directly, without building a Row for every row.
+type ColumnSink interface {
+	// Append appends the parsed value of the field to the current row.
+	Append(fieldID int64, value any) error
+	// EndRow is called after all the values of the current row are appended.
+	EndRow() error
+	// DiscardRow drops the values appended to the current row, it's called if the row is invalid.
+	DiscardRow()
+}
+
+// rowSink collects the values of a single row into a Row.
+type rowSink Row
+
+func (s rowSink) Append(fieldID int64, value any) error {
+	s[fieldID] = value
+	return nil
+}
+
+func (s rowSink) EndRow() error {
+	return nil
+}
+
+func (s rowSink) DiscardRow() {}
+
+// ParseToSink parses the rows and appends the values into the sink, parsing stops at the
+// first invalid row, whose values appended already are discarded by the sink.
+func (r *rowParser) ParseToSink(raws []any, sink ColumnSink) error {
+	for i, raw := range raws {
+		err := r.parseRowToSink(raw, sink)
+		if err == nil {
+			err = sink.EndRow()
+		}
+		if err != nil {
+			sink.DiscardRow()
+			return withRowIndex(err, i, merr.WrapErrImportFailed(fmt.Sprintf("failed to parse row %d, error: %v", i, err)))
+		}
+	}
+	return nil
+}
+
+// parseRowToSink parses the row into the sink directly, unless the computed fields or
+// the row size limit are set, which need the whole row, the Row is built then.
+func (r *rowParser) parseRowToSink(raw any, sink ColumnSink) error {
+	if len(r.option.computedFields) == 0 && r.option.maxRowBytes <= 0 {
+		return r.parseInto(raw, nil, sink)
+	}
+	row, err := r.parse(raw, nil)
+	if err != nil {
+		return err
+	}
+	for fieldID, value := range row {
+		if err = sink.Append(fieldID, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ColumnBuffers holds parsed rows column by column, each column is a typed slice keyed by field id.
+// BinaryVector, Float16Vector and JSON values are stored in Bytes.
+type ColumnBuffers struct {
+	NumRows int
+
+	Bool        map[int64][]bool
+	Int8        map[int64][]int8
+	Int16       map[int64][]int16
+	Int32       map[int64][]int32
+	Int64       map[int64][]int64
+	Float       map[int64][]float32
+	Double      map[int64][]float64
+	String      map[int64][]string
+	Bytes       map[int64][][]byte
+	FloatVector map[int64][][]float32
+	Array       map[int64][]*schemapb.ScalarField
+
+	// the fields appended to the current row
+	pending []int64
+}
+
+func NewColumnBuffers() *ColumnBuffers {
+	return &ColumnBuffers{
+		Bool:        make(map[int64][]bool),
+		Int8:        make(map[int64][]int8),
+		Int16:       make(map[int64][]int16),
+		Int32:       make(map[int64][]int32),
+		Int64:       make(map[int64][]int64),
+		Float:       make(map[int64][]float32),
+		Double:      make(map[int64][]float64),
+		String:      make(map[int64][]string),
+		Bytes:       make(map[int64][][]byte),
+		FloatVector: make(map[int64][][]float32),
+		Array:       make(map[int64][]*schemapb.ScalarField),
+	}
+}
+
+// Append appends the value to the typed column of the field.
+func (c *ColumnBuffers) Append(fieldID int64, value any) error {
+	if fieldID == DeletedFieldID {
+		return merr.WrapErrImportFailed("the deletion row cannot be appended to the columns")
+	}
+	if err := c.append(fieldID, value); err != nil {
+		return err
+	}
+	c.pending = append(c.pending, fieldID)
+	return nil
+}
+
+func (c *ColumnBuffers) EndRow() error {
+	c.NumRows++
+	c.pending = c.pending[:0]
+	return nil
+}
+
+// DiscardRow truncates the columns appended by the current row, so all the columns keep NumRows values.
+func (c *ColumnBuffers) DiscardRow() {
+	for _, fieldID := range c.pending {
+		truncateColumn(c.Bool, fieldID, c.NumRows)
+		truncateColumn(c.Int8, fieldID, c.NumRows)
+		truncateColumn(c.Int16, fieldID, c.NumRows)
+		truncateColumn(c.Int32, fieldID, c.NumRows)
+		truncateColumn(c.Int64, fieldID, c.NumRows)
+		truncateColumn(c.Float, fieldID, c.NumRows)
+		truncateColumn(c.Double, fieldID, c.NumRows)
+		truncateColumn(c.String, fieldID, c.NumRows)
+		truncateColumn(c.Bytes, fieldID, c.NumRows)
+		truncateColumn(c.FloatVector, fieldID, c.NumRows)
+		truncateColumn(c.Array, fieldID, c.NumRows)
+	}
+	c.pending = c.pending[:0]
+}
+
+func truncateColumn[T any](columns map[int64][]T, fieldID int64, n int) {
+	if column, ok := columns[fieldID]; ok && len(column) > n {
+		columns[fieldID] = column[:n]
+	}
+}
+
+func (c *ColumnBuffers) append(fieldID int64, value any) error {
+	switch v := value.(type) {
+	case bool:
+		c.Bool[fieldID] = append(c.Bool[fieldID], v)
+	case int8:
+		c.Int8[fieldID] = append(c.Int8[fieldID], v)
+	case int16:
+		c.Int16[fieldID] = append(c.Int16[fieldID], v)
+	case int32:
+		c.Int32[fieldID] = append(c.Int32[fieldID], v)
+	case int64:
+		c.Int64[fieldID] = append(c.Int64[fieldID], v)
+	case float32:
+		c.Float[fieldID] = append(c.Float[fieldID], v)
+	case float64:
+		c.Double[fieldID] = append(c.Double[fieldID], v)
+	case string:
+		c.String[fieldID] = append(c.String[fieldID], v)
+	case []byte:
+		c.Bytes[fieldID] = append(c.Bytes[fieldID], v)
+	case []float32:
+		c.FloatVector[fieldID] = append(c.FloatVector[fieldID], v)
+	case *schemapb.ScalarField:
+		c.Array[fieldID] = append(c.Array[fieldID], v)
+	default:
+		return merr.WrapErrImportFailed(fmt.Sprintf("unexpected value type '%T' for field %d", value, fieldID))
+	}
+	return nil
+}
+
+// ParseColumnar parses the rows and appends the values into the typed columns.
+// A row is either appended entirely or not at all, parsing stops at the first invalid row.
+func (r *rowParser) ParseColumnar(raws []any, columns *ColumnBuffers) error {
+	return r.ParseToSink(raws, columns)
+}
@@ -0,0 +1,56 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+const (
+	CodecGzip = "gzip"
+	CodecZstd = "zstd"
+)
+
+// ParseStreamCompressed decompresses the stream with the codec, CodecGzip or CodecZstd, and parses
+// the rows like ParseStream. The byte offsets passed to the checkpoint and the seek offset are
+// positions in the decompressed stream.
+func (s *StreamParser) ParseStreamCompressed(r io.Reader, codec string, emit func(Row) error) error {
+	switch codec {
+	case CodecGzip:
+		reader, err := gzip.NewReader(r)
+		if err != nil {
+			return merr.WrapErrImportFailed(fmt.Sprintf("failed to open the %s stream, error: %v", codec, err))
+		}
+		defer reader.Close()
+		return s.ParseStream(reader, emit)
+	case CodecZstd:
+		reader, err := zstd.NewReader(r)
+		if err != nil {
+			return merr.WrapErrImportFailed(fmt.Sprintf("failed to open the %s stream, error: %v", codec, err))
+		}
+		defer reader.Close()
+		return s.ParseStream(reader, emit)
+	default:
+		return merr.WrapErrImportFailed(fmt.Sprintf("unsupported codec '%s', supported codecs: [%s %s]", codec, CodecGzip, CodecZstd))
+	}
+}
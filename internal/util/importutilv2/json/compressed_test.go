@@ -0,0 +1,66 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamParser_ParseStreamCompressed(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	sp := NewStreamParser(parser)
+	parse := func(data []byte, codec string) ([]int64, error) {
+		ids := make([]int64, 0)
+		err := sp.ParseStreamCompressed(bytes.NewReader(data), codec, func(row Row) error {
+			ids = append(ids, row[100].(int64))
+			return nil
+		})
+		return ids, err
+	}
+
+	var gz bytes.Buffer
+	writer := gzip.NewWriter(&gz)
+	_, err = writer.Write([]byte(newTestLines(3)))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	ids, err := parse(gz.Bytes(), CodecGzip)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{0, 1, 2}, ids)
+
+	encoder, err := zstd.NewWriter(nil)
+	assert.NoError(t, err)
+	ids, err = parse(encoder.EncodeAll([]byte(newTestLines(3)), nil), CodecZstd)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{0, 1, 2}, ids)
+
+	// the truncated stream fails at the row being read
+	ids, err = parse(gz.Bytes()[:gz.Len()-8], CodecGzip)
+	assert.ErrorContains(t, err, "failed to read row")
+	assert.Equal(t, []int64{0, 1, 2}, ids)
+
+	_, err = parse([]byte(newTestLines(1)), CodecGzip)
+	assert.ErrorContains(t, err, "failed to open the gzip stream")
+	err = sp.ParseStreamCompressed(strings.NewReader(""), "lz4", nil)
+	assert.ErrorContains(t, err, "unsupported codec 'lz4'")
+}
@@ -0,0 +1,77 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"fmt"
+
+	"github.com/milvus-io/milvus/pkg/common"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// DeletedFieldID is the key of the deletion indicator in the row parsed from a delete marker row.
+// It's in the range of the system fields, so it never conflicts with the user fields.
+const DeletedFieldID int64 = common.StartOfUserFieldID - 1
+
+// IsDeleted returns whether the row is parsed from a delete marker row, such a row
+// only contains the primary key and the deletion indicator.
+func IsDeleted(row Row) bool {
+	deleted, ok := row[DeletedFieldID].(bool)
+	return ok && deleted
+}
+
+func (r *rowParser) checkDeleteMarker() error {
+	key := r.option.deleteMarker
+	if key == "" {
+		return nil
+	}
+	if _, ok := r.name2FieldID[key]; ok || key == r.pkField.GetName() {
+		return merr.WrapErrImportFailed(fmt.Sprintf("the delete marker '%s' conflicts with the field of the same name", key))
+	}
+	if _, ok := r.aliasOf(key); ok {
+		return merr.WrapErrImportFailed(fmt.Sprintf("the delete marker '%s' conflicts with the alias of the same name", key))
+	}
+	return nil
+}
+
+// parseDeleteMarker checks the delete marker of the row. If the row is a deletion, it returns the
+// row with the primary key and the deletion indicator, the other keys of the row are ignored.
+func (r *rowParser) parseDeleteMarker(stringMap map[string]any) (Row, bool, error) {
+	marker, ok := stringMap[r.option.deleteMarker]
+	if !ok {
+		return nil, false, nil
+	}
+	deleted, ok := marker.(bool)
+	if !ok {
+		return nil, false, merr.WrapErrImportFailed(fmt.Sprintf("the delete marker '%s' must be a bool, got '%v'",
+			r.option.deleteMarker, marker))
+	}
+	if !deleted {
+		return nil, false, nil
+	}
+	// the primary key is required even if it's auto-generated, it identifies the entity to delete
+	value, ok := r.lookupValue(stringMap, r.pkField.GetName())
+	if !ok {
+		return nil, false, newParseError(ErrKindMissingField, r.pkField, nil, merr.WrapErrImportFailed(
+			fmt.Sprintf("value of primary key '%s' is missed in the deletion row", r.pkField.GetName())))
+	}
+	pk, err := r.parseEntity(r.pkField.GetFieldID(), value)
+	if err != nil {
+		return nil, false, err
+	}
+	return Row{r.pkField.GetFieldID(): pk, DeletedFieldID: true}, true, nil
+}
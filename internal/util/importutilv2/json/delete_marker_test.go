@@ -0,0 +1,75 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestRowParser_DeleteMarker(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "name", DataType: schemapb.DataType_VarChar},
+		&schemapb.FieldSchema{FieldID: 103, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+	)
+	parser, err := NewRowParser(schema, WithDeleteMarker("_deleted"))
+	assert.NoError(t, err)
+
+	// the other fields are not required by a deletion
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "_deleted": true}`))
+	assert.NoError(t, err)
+	assert.True(t, IsDeleted(row))
+	assert.Equal(t, Row{100: int64(1), DeletedFieldID: true}, row)
+	row, err = parser.Parse(decodeRow(t, `{"id": 2, "name": "a", "_deleted": true}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(row))
+
+	// the marker is not stored in the dynamic field
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": "a", "_deleted": false}`))
+	assert.NoError(t, err)
+	assert.False(t, IsDeleted(row))
+	assert.Equal(t, "{}", string(row[103].([]byte)))
+	row, err = parser.ParseRaw(map[string]json.RawMessage{"id": json.RawMessage("3"), "_deleted": json.RawMessage("true")})
+	assert.NoError(t, err)
+	assert.True(t, IsDeleted(row))
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": "a"}`))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "_deleted": 1}`))
+	assert.ErrorContains(t, err, "the delete marker '_deleted' must be a bool, got '1'")
+	_, err = parser.Parse(decodeRow(t, `{"_deleted": true}`))
+	assert.ErrorContains(t, err, "value of primary key 'id' is missed in the deletion row")
+	_, err = parser.Parse(decodeRow(t, `{"id": "a", "_deleted": true}`))
+	assert.Error(t, err)
+
+	// the primary key is provided to delete even if it's auto-generated
+	schema.Fields[0].AutoID = true
+	parser, err = NewRowParser(schema, WithDeleteMarker("_deleted"))
+	assert.NoError(t, err)
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "_deleted": true}`))
+	assert.NoError(t, err)
+	assert.True(t, IsDeleted(row))
+
+	_, err = NewRowParser(schema, WithDeleteMarker("name"))
+	assert.ErrorContains(t, err, "the delete marker 'name' conflicts with the field of the same name")
+	_, err = NewRowParser(schema, WithDeleteMarker("del"), WithAliases(map[string][]string{"name": {"del"}}))
+	assert.ErrorContains(t, err, "the delete marker 'del' conflicts with the alias of the same name")
+}
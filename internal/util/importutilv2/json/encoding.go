@@ -0,0 +1,187 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// VectorEncoding is the encoding of the vectors given as strings.
+type VectorEncoding string
+
+const (
+	VectorEncodingBase64 VectorEncoding = "base64"
+	VectorEncodingHex    VectorEncoding = "hex"
+	// VectorEncodingRepr is the NumPy array repr, e.g. "[0.1 0.2 0.3]", only for float vectors.
+	VectorEncodingRepr VectorEncoding = "repr"
+)
+
+const base64Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/="
+
+func (r *rowParser) checkVectorEncodings() error {
+	for fieldID, encoding := range r.option.vectorEncodings {
+		switch r.id2Field[fieldID].GetDataType() {
+		case schemapb.DataType_BinaryVector, schemapb.DataType_FloatVector, schemapb.DataType_Float16Vector, schemapb.DataType_BFloat16Vector:
+		default:
+			return merr.WrapErrImportFailed(fmt.Sprintf("encoding is only supported for vector field, field id: %d", fieldID))
+		}
+		switch encoding {
+		case VectorEncodingBase64, VectorEncodingHex:
+		case VectorEncodingRepr:
+			if r.id2Field[fieldID].GetDataType() != schemapb.DataType_FloatVector {
+				return merr.WrapErrImportFailed(fmt.Sprintf("repr encoding is only supported for float vector field, field id: %d", fieldID))
+			}
+		default:
+			return merr.WrapErrImportFailed(fmt.Sprintf("unsupported vector encoding '%s'", encoding))
+		}
+	}
+	return nil
+}
+
+// snippet returns the characters around the position, so that users can find it in the file.
+func snippet(str string, pos int) string {
+	start, end := pos-8, pos+8
+	if start < 0 {
+		start = 0
+	}
+	if end > len(str) {
+		end = len(str)
+	}
+	return str[start:end]
+}
+
+// decodeVectorString decodes the string, the errors are reported with the position of the
+// first invalid character, because the encodings are hard to check by eye.
+func (r *rowParser) decodeVectorString(encoding VectorEncoding, str string, fieldID int64) ([]byte, error) {
+	name := r.id2Field[fieldID].GetName()
+	invalidCharErr := func(pos int) error {
+		return newParseError(ErrKindInvalidValue, r.id2Field[fieldID], str, merr.WrapErrImportFailed(
+			fmt.Sprintf("invalid %s vector of field '%s': illegal character '%c' at position %d near '%s'",
+				encoding, name, str[pos], pos, snippet(str, pos))))
+	}
+	truncatedErr := func() error {
+		return newParseError(ErrKindInvalidValue, r.id2Field[fieldID], str, merr.WrapErrImportFailed(
+			fmt.Sprintf("invalid %s vector of field '%s': the string of length %d is truncated or corrupt, "+
+				"please check the value is completely exported", encoding, name, len(str))))
+	}
+	switch encoding {
+	case VectorEncodingBase64:
+		for i := 0; i < len(str); i++ {
+			if !strings.ContainsRune(base64Alphabet, rune(str[i])) {
+				return nil, invalidCharErr(i)
+			}
+		}
+		if len(str)%4 != 0 {
+			return nil, truncatedErr()
+		}
+		bytes, err := base64.StdEncoding.DecodeString(str)
+		corruptErr := base64.CorruptInputError(0)
+		if errors.As(err, &corruptErr) {
+			return nil, invalidCharErr(int(corruptErr))
+		}
+		return bytes, err
+	default:
+		bytes, err := hex.DecodeString(str)
+		invalidByteErr := hex.InvalidByteError(0)
+		if errors.As(err, &invalidByteErr) {
+			return nil, invalidCharErr(strings.IndexByte(str, byte(invalidByteErr)))
+		}
+		if errors.Is(err, hex.ErrLength) {
+			return nil, truncatedErr()
+		}
+		return bytes, err
+	}
+}
+
+// parseReprVector parses a float vector given as a NumPy array repr, the components are
+// separated by whitespaces, including the line breaks inserted by NumPy for long arrays.
+func (r *rowParser) parseReprVector(str string, fieldID int64) (any, error) {
+	name := r.id2Field[fieldID].GetName()
+	trimmed := strings.TrimSpace(str)
+	if !strings.HasPrefix(trimmed, "[") || !strings.HasSuffix(trimmed, "]") {
+		return nil, newParseError(ErrKindInvalidValue, r.id2Field[fieldID], str, merr.WrapErrImportFailed(
+			fmt.Sprintf("invalid repr vector of field '%s': the value must be enclosed in brackets", name)))
+	}
+	tokens := strings.Fields(trimmed[1 : len(trimmed)-1])
+	if len(tokens) == 0 {
+		return nil, r.wrapEmptyVectorError(fieldID)
+	}
+	vec := make([]float32, len(tokens))
+	for i, token := range tokens {
+		if token == "..." {
+			return nil, newParseError(ErrKindInvalidValue, r.id2Field[fieldID], str, merr.WrapErrImportFailed(
+				fmt.Sprintf("invalid repr vector of field '%s': the repr is summarized with '...' at index %d, "+
+					"please print the array with numpy.set_printoptions(threshold=sys.maxsize)", name, i)))
+		}
+		num, err := strconv.ParseFloat(token, 32)
+		if err != nil || math.IsNaN(num) || math.IsInf(num, 0) {
+			return nil, newParseError(ErrKindInvalidValue, r.id2Field[fieldID], str, merr.WrapErrImportFailed(
+				fmt.Sprintf("invalid repr vector of field '%s': illegal component '%s' at index %d", name, token, i)))
+		}
+		vec[i] = float32(num)
+	}
+	if len(vec) != r.dims[fieldID] {
+		return nil, r.wrapDimError(len(vec), fieldID)
+	}
+	return vec, nil
+}
+
+// parseEncodedVector parses a vector given as an encoded string of its bytes.
+func (r *rowParser) parseEncodedVector(encoding VectorEncoding, str string, fieldID int64) (any, error) {
+	if encoding == VectorEncodingRepr {
+		return r.parseReprVector(str, fieldID)
+	}
+	bytes, err := r.decodeVectorString(encoding, str, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes) == 0 {
+		return nil, r.wrapEmptyVectorError(fieldID)
+	}
+	switch r.id2Field[fieldID].GetDataType() {
+	case schemapb.DataType_BinaryVector:
+		if len(bytes)*8 != r.dims[fieldID] {
+			return nil, r.wrapDimError(len(bytes)*8, fieldID)
+		}
+		return bytes, nil
+	case schemapb.DataType_Float16Vector, schemapb.DataType_BFloat16Vector:
+		if len(bytes) != r.dims[fieldID]*2 {
+			return nil, r.wrapDimError(len(bytes)/2, fieldID)
+		}
+		return bytes, nil
+	default:
+		if len(bytes) != r.dims[fieldID]*4 {
+			return nil, r.wrapDimError(len(bytes)/4, fieldID)
+		}
+		order := r.byteOrder(fieldID)
+		vec := make([]float32, len(bytes)/4)
+		for i := range vec {
+			vec[i] = math.Float32frombits(order.Uint32(bytes[i*4:]))
+		}
+		return vec, nil
+	}
+}
@@ -0,0 +1,139 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+)
+
+func TestRowParser_VectorEncoding(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{
+		FieldID:    102,
+		Name:       "bin",
+		DataType:   schemapb.DataType_BinaryVector,
+		TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "16"}},
+	})
+	parser, err := NewRowParser(schema, WithVectorEncoding(VectorEncodingBase64, 101), WithVectorEncoding(VectorEncodingHex, 102))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": "AACAPwAAAEA=", "bin": "0aff"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{1, 2}, row[101])
+	assert.Equal(t, []byte{0x0a, 0xff}, row[102])
+
+	// arrays are still accepted
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [1, 2], "bin": [10, 255]}`))
+	assert.NoError(t, err)
+
+	for _, c := range []struct {
+		vector string
+		bin    string
+		expect string
+	}{
+		{"AACAPwAAAE", "0aff", "invalid base64 vector of field 'vector': the string of length 10 is truncated or corrupt"},
+		{"AACAPw!AAEA=", "0aff", "invalid base64 vector of field 'vector': illegal character '!' at position 6 near 'AACAPw!AAEA='"},
+		{"AACA=wAAAEA=", "0aff", "illegal character '=' at position 4"},
+		{"AACAPwAAAEA=", "0af", "invalid hex vector of field 'bin': the string of length 3 is truncated or corrupt"},
+		{"AACAPwAAAEA=", "0agf", "invalid hex vector of field 'bin': illegal character 'g' at position 2 near '0agf'"},
+		{"AACAPw==", "0aff", "expected dim '2' for field 'vector' with type 'FloatVector', got dim '1'"},
+		{"", "0aff", "empty vector for field 'vector'"},
+	} {
+		_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": "`+c.vector+`", "bin": "`+c.bin+`"}`))
+		assert.ErrorContains(t, err, c.expect)
+	}
+
+	_, err = NewRowParser(schema, WithVectorEncoding(VectorEncodingHex, 100))
+	assert.ErrorContains(t, err, "encoding is only supported for vector field")
+	_, err = NewRowParser(schema, WithVectorEncoding("base32", 101))
+	assert.ErrorContains(t, err, "unsupported vector encoding 'base32'")
+}
+
+func TestRowParser_HalfVectorEncoding(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{
+			FieldID:    102,
+			Name:       "fp16",
+			DataType:   schemapb.DataType_Float16Vector,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}},
+		},
+		&schemapb.FieldSchema{
+			FieldID:    103,
+			Name:       "bf16",
+			DataType:   schemapb.DataType_BFloat16Vector,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}},
+		},
+	)
+	parser, err := NewRowParser(schema, WithVectorEncoding(VectorEncodingHex, 102), WithVectorEncoding(VectorEncodingBase64, 103))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "fp16": "003c00c0", "bf16": "gD/NPQ=="}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x3c, 0x00, 0xc0}, row[102])
+	assert.Equal(t, []byte{0x80, 0x3f, 0xcd, 0x3d}, row[103])
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "fp16": "003c00c0", "bf16": "gD/N"}`))
+	assert.ErrorContains(t, err, "expected dim '2' for field 'bf16' with type 'BFloat16Vector', got dim '1'")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "fp16": "003c00c0", "bf16": "gD/NP!=="}`))
+	assert.ErrorContains(t, err, "invalid base64 vector of field 'bf16': illegal character '!' at position 5")
+
+	_, err = NewRowParser(schema, WithVectorEncoding(VectorEncodingRepr, 103))
+	assert.ErrorContains(t, err, "repr encoding is only supported for float vector field")
+}
+
+func TestRowParser_ReprVector(t *testing.T) {
+	schema := newTestSchema()
+	schema.Fields[1].TypeParams[0].Value = "3"
+	parser, err := NewRowParser(schema, WithVectorEncoding(VectorEncodingRepr, 101))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": "[0.1 -2.  3e-01]"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.1, -2, 0.3}, row[101])
+	// NumPy breaks long arrays into lines and pads the components
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": " [ 0.1  0.2\n  0.3 ]"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.1, 0.2, 0.3}, row[101])
+	// the arrays are still accepted
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2, 0.3]}`))
+	assert.NoError(t, err)
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": "[0.1 0.2]"}`))
+	assert.ErrorContains(t, err, "expected dim '3'")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": "[0.1, 0.2, 0.3]"}`))
+	assert.ErrorContains(t, err, "illegal component '0.1,' at index 0")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": "[0.1 nan 0.3]"}`))
+	assert.ErrorContains(t, err, "illegal component 'nan' at index 1")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": "[0.1 ... 0.3]"}`))
+	assert.ErrorContains(t, err, "the repr is summarized with '...' at index 1")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": "0.1 0.2 0.3"}`))
+	assert.ErrorContains(t, err, "the value must be enclosed in brackets")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": "[ ]"}`))
+	assert.ErrorContains(t, err, "empty")
+
+	schema = newTestSchema(&schemapb.FieldSchema{
+		FieldID: 102, Name: "bin", DataType: schemapb.DataType_BinaryVector,
+		TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "8"}},
+	})
+	_, err = NewRowParser(schema, WithVectorEncoding(VectorEncodingRepr, 102))
+	assert.ErrorContains(t, err, "repr encoding is only supported for float vector field")
+}
@@ -0,0 +1,106 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+// ParseErrorKind is the category of a ParseError.
+type ParseErrorKind string
+
+const (
+	ErrKindTypeMismatch ParseErrorKind = "type_mismatch"
+	ErrKindDimMismatch  ParseErrorKind = "dim_mismatch"
+	ErrKindEmptyVector  ParseErrorKind = "empty_vector"
+	ErrKindInvalidValue ParseErrorKind = "invalid_value"
+	ErrKindMissingField ParseErrorKind = "missing_field"
+	ErrKindUnknownField ParseErrorKind = "unknown_field"
+	ErrKindOther        ParseErrorKind = "other"
+)
+
+// ParseError carries the details of a parse error in a machine-readable way,
+// its Error() is the same message as the plain error, which is for logs.
+// RowIndex is -1 if the error is returned by Parse, which knows nothing about the row index.
+type ParseError struct {
+	RowIndex  int
+	FieldName string
+	FieldType string
+	Kind      ParseErrorKind
+	Value     string
+
+	err error
+}
+
+func newParseError(kind ParseErrorKind, field *schemapb.FieldSchema, value any, err error) *ParseError {
+	e := &ParseError{
+		RowIndex: -1,
+		Kind:     kind,
+		err:      err,
+	}
+	if field != nil {
+		e.FieldName = field.GetName()
+		e.FieldType = field.GetDataType().String()
+	}
+	if value != nil {
+		e.Value = fmt.Sprintf("%v", value)
+	}
+	return e
+}
+
+func (e *ParseError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.err
+}
+
+func (e *ParseError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		RowIndex  int            `json:"row_index"`
+		FieldName string         `json:"field_name,omitempty"`
+		FieldType string         `json:"field_type,omitempty"`
+		Kind      ParseErrorKind `json:"kind"`
+		Value     string         `json:"value,omitempty"`
+		Message   string         `json:"message"`
+	}{
+		RowIndex:  e.RowIndex,
+		FieldName: e.FieldName,
+		FieldType: e.FieldType,
+		Kind:      e.Kind,
+		Value:     e.Value,
+		Message:   e.Error(),
+	})
+}
+
+// withRowIndex returns the wrapped error, the details are kept if err is a ParseError.
+func withRowIndex(err error, rowIndex int, wrapped error) error {
+	parseErr := &ParseError{}
+	if !errors.As(err, &parseErr) {
+		return wrapped
+	}
+	res := *parseErr
+	res.RowIndex = rowIndex
+	res.err = wrapped
+	return &res
+}
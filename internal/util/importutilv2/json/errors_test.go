@@ -0,0 +1,71 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func TestParseError(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2, 0.3]}`))
+	parseErr := &ParseError{}
+	assert.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, -1, parseErr.RowIndex)
+	assert.Equal(t, ErrKindDimMismatch, parseErr.Kind)
+	assert.Equal(t, "3", parseErr.Value)
+
+	_, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2]}`,
+		`{"id": "a", "vector": [0.1, 0.2]}`,
+	))
+	assert.ErrorContains(t, err, "failed to parse row 1")
+	assert.True(t, errors.Is(err, merr.ErrImportFailed))
+	assert.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, 1, parseErr.RowIndex)
+	assert.Equal(t, "id", parseErr.FieldName)
+	assert.Equal(t, "Int64", parseErr.FieldType)
+	assert.Equal(t, ErrKindTypeMismatch, parseErr.Kind)
+	assert.Equal(t, "a", parseErr.Value)
+
+	bytes, err := json.Marshal(parseErr)
+	assert.NoError(t, err)
+	res := make(map[string]any)
+	assert.NoError(t, json.Unmarshal(bytes, &res))
+	assert.Equal(t, float64(1), res["row_index"])
+	assert.Equal(t, "id", res["field_name"])
+	assert.Equal(t, "type_mismatch", res["kind"])
+	assert.Contains(t, res["message"], "failed to parse row 1")
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "x": 1}`))
+	assert.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, ErrKindUnknownField, parseErr.Kind)
+	assert.Equal(t, "x", parseErr.FieldName)
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1}`))
+	assert.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, ErrKindMissingField, parseErr.Kind)
+	assert.Equal(t, "vector", parseErr.FieldName)
+}
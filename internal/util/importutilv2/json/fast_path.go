@@ -0,0 +1,106 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+// pkVectorFields is the layout of the most common schema, which has only a primary key
+// and a float vector, rows of such schema are parsed without the general machinery.
+type pkVectorFields struct {
+	pkID    int64
+	pkName  string
+	pkType  schemapb.DataType
+	vecID   int64
+	vecName string
+	dim     int
+}
+
+// newPKVectorFields returns the layout if the fast path applies, i.e. the schema has only a
+// primary key provided by rows and a float vector, and no option is set.
+func newPKVectorFields(schema *schemapb.CollectionSchema, numOpts int) *pkVectorFields {
+	fields := schema.GetFields()
+	if numOpts > 0 || len(fields) != 2 || schema.GetEnableDynamicField() {
+		return nil
+	}
+	pk, vec := fields[0], fields[1]
+	if !pk.GetIsPrimaryKey() {
+		pk, vec = vec, pk
+	}
+	if !pk.GetIsPrimaryKey() || pk.GetAutoID() || pk.GetIsPartitionKey() || vec.GetDataType() != schemapb.DataType_FloatVector {
+		return nil
+	}
+	if pk.GetDataType() != schemapb.DataType_Int64 && pk.GetDataType() != schemapb.DataType_VarChar {
+		return nil
+	}
+	return &pkVectorFields{
+		pkID:    pk.GetFieldID(),
+		pkName:  pk.GetName(),
+		pkType:  pk.GetDataType(),
+		vecID:   vec.GetFieldID(),
+		vecName: vec.GetName(),
+	}
+}
+
+// parseFast parses the row of the fast path schema, it returns false if the row is not a
+// well-formed one, and the general path should be taken to report the error.
+func (r *rowParser) parseFast(stringMap map[string]any) (Row, bool) {
+	f := r.fastFields
+	if len(stringMap) != 2 {
+		return nil, false
+	}
+	var pk any
+	switch v := stringMap[f.pkName].(type) {
+	case json.Number:
+		if f.pkType != schemapb.DataType_Int64 {
+			return nil, false
+		}
+		num, err := strconv.ParseInt(v.String(), 0, 64)
+		if err != nil {
+			return nil, false
+		}
+		pk = num
+	case string:
+		if f.pkType != schemapb.DataType_VarChar {
+			return nil, false
+		}
+		pk = v
+	default:
+		return nil, false
+	}
+	arr, ok := stringMap[f.vecName].([]any)
+	if !ok || len(arr) == 0 || len(arr) != f.dim {
+		return nil, false
+	}
+	vec := make([]float32, len(arr))
+	for i, elem := range arr {
+		value, ok := elem.(json.Number)
+		if !ok {
+			return nil, false
+		}
+		num, err := strconv.ParseFloat(value.String(), 32)
+		if err != nil {
+			return nil, false
+		}
+		vec[i] = float32(num)
+	}
+	return Row{f.pkID: pk, f.vecID: vec}, true
+}
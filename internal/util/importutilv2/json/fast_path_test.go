@@ -0,0 +1,103 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestRowParser_FastPath(t *testing.T) {
+	for _, pkType := range []schemapb.DataType{schemapb.DataType_Int64, schemapb.DataType_VarChar} {
+		schema := newTestSchema()
+		schema.Fields[0].DataType = pkType
+		parser, err := NewRowParser(schema)
+		assert.NoError(t, err)
+		assert.NotNil(t, parser.(*rowParser).fastFields)
+
+		id := "1"
+		if pkType == schemapb.DataType_VarChar {
+			id = `"1"`
+		}
+		raw := fmt.Sprintf(`{"id": %s, "vector": [0.1, 0.2]}`, id)
+		row, err := parser.Parse(decodeRow(t, raw))
+		assert.NoError(t, err)
+		general, err := parser.(*rowParser).parse(decodeRow(t, raw), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, general, row)
+
+		// the errors are reported by the general path
+		_, err = parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": %s, "vector": [0.1]}`, id)))
+		assert.ErrorContains(t, err, "expected dim '2' for field 'vector'")
+		_, err = parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": %s, "vector": [0.1, 0.2], "x": 1}`, id)))
+		assert.ErrorContains(t, err, "the field 'x' is not defined in schema")
+		_, err = parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": %s}`, id)))
+		assert.ErrorContains(t, err, "value of field 'vector' is missed")
+		_, err = parser.Parse(decodeRow(t, `{"id": true, "vector": [0.1, 0.2]}`))
+		assert.ErrorContains(t, err, "expected type")
+	}
+
+	// the general path is taken for other schemas or with options
+	parser, err := NewRowParser(newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "a", DataType: schemapb.DataType_Int64}))
+	assert.NoError(t, err)
+	assert.Nil(t, parser.(*rowParser).fastFields)
+	parser, err = NewRowParser(newTestSchema(), WithBoolAsInteger())
+	assert.NoError(t, err)
+	assert.Nil(t, parser.(*rowParser).fastFields)
+	schema := newTestSchema()
+	schema.Fields[0].AutoID = true
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	assert.Nil(t, parser.(*rowParser).fastFields)
+}
+
+func benchmarkPKVectorRows(b *testing.B, opts ...RowParserOption) {
+	schema := newTestSchema()
+	schema.Fields[1].TypeParams[0].Value = "128"
+	parser, err := NewRowParser(schema, opts...)
+	assert.NoError(b, err)
+	components := make([]string, 128)
+	for i := range components {
+		components[i] = fmt.Sprintf("0.%d", i)
+	}
+	raws := make([]any, 0, 100)
+	for i := 0; i < 100; i++ {
+		raws = append(raws, decodeRow(&testing.T{}, fmt.Sprintf(`{"id": %d, "vector": [%s]}`, i, strings.Join(components, ","))))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, raw := range raws {
+			if _, err := parser.Parse(raw); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkRowParser_FastPath(b *testing.B) {
+	benchmarkPKVectorRows(b)
+}
+
+func BenchmarkRowParser_GeneralPath(b *testing.B) {
+	// any option disables the fast path
+	benchmarkPKVectorRows(b, WithIgnoreKeys())
+}
@@ -0,0 +1,233 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// newFieldData returns an empty FieldData of the field, the values are appended by appendFieldData.
+func (r *rowParser) newFieldData(field *schemapb.FieldSchema) *schemapb.FieldData {
+	fieldData := &schemapb.FieldData{
+		Type:      field.GetDataType(),
+		FieldName: field.GetName(),
+		FieldId:   field.GetFieldID(),
+		IsDynamic: field.GetIsDynamic(),
+	}
+	dim := int64(r.dims[field.GetFieldID()])
+	switch field.GetDataType() {
+	case schemapb.DataType_Bool:
+		fieldData.Field = &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_BoolData{BoolData: &schemapb.BoolArray{}},
+		}}
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32:
+		fieldData.Field = &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_IntData{IntData: &schemapb.IntArray{}},
+		}}
+	case schemapb.DataType_Int64:
+		fieldData.Field = &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{}},
+		}}
+	case schemapb.DataType_Float:
+		fieldData.Field = &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_FloatData{FloatData: &schemapb.FloatArray{}},
+		}}
+	case schemapb.DataType_Double:
+		fieldData.Field = &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_DoubleData{DoubleData: &schemapb.DoubleArray{}},
+		}}
+	case schemapb.DataType_String, schemapb.DataType_VarChar:
+		fieldData.Field = &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{}},
+		}}
+	case schemapb.DataType_JSON:
+		fieldData.Field = &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_JsonData{JsonData: &schemapb.JSONArray{}},
+		}}
+	case schemapb.DataType_Array:
+		fieldData.Field = &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_ArrayData{ArrayData: &schemapb.ArrayArray{ElementType: field.GetElementType()}},
+		}}
+	case schemapb.DataType_FloatVector:
+		fieldData.Field = &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+			Dim:  dim,
+			Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{}},
+		}}
+	case schemapb.DataType_BinaryVector:
+		fieldData.Field = &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+			Dim:  dim,
+			Data: &schemapb.VectorField_BinaryVector{},
+		}}
+	case schemapb.DataType_Float16Vector:
+		fieldData.Field = &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+			Dim:  dim,
+			Data: &schemapb.VectorField_Float16Vector{},
+		}}
+	case schemapb.DataType_BFloat16Vector:
+		fieldData.Field = &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+			Dim:  dim,
+			Data: &schemapb.VectorField_Bfloat16Vector{},
+		}}
+	}
+	return fieldData
+}
+
+// appendFieldData appends a parsed value to the FieldData created by newFieldData.
+func appendFieldData(fieldData *schemapb.FieldData, value any) error {
+	scalars, vectors := fieldData.GetScalars(), fieldData.GetVectors()
+	ok := true
+	switch fieldData.GetType() {
+	case schemapb.DataType_Bool:
+		var v bool
+		if v, ok = value.(bool); ok {
+			scalars.GetBoolData().Data = append(scalars.GetBoolData().Data, v)
+		}
+	case schemapb.DataType_Int8:
+		var v int8
+		if v, ok = value.(int8); ok {
+			scalars.GetIntData().Data = append(scalars.GetIntData().Data, int32(v))
+		}
+	case schemapb.DataType_Int16:
+		var v int16
+		if v, ok = value.(int16); ok {
+			scalars.GetIntData().Data = append(scalars.GetIntData().Data, int32(v))
+		}
+	case schemapb.DataType_Int32:
+		var v int32
+		if v, ok = value.(int32); ok {
+			scalars.GetIntData().Data = append(scalars.GetIntData().Data, v)
+		}
+	case schemapb.DataType_Int64:
+		var v int64
+		if v, ok = value.(int64); ok {
+			scalars.GetLongData().Data = append(scalars.GetLongData().Data, v)
+		}
+	case schemapb.DataType_Float:
+		var v float32
+		if v, ok = value.(float32); ok {
+			scalars.GetFloatData().Data = append(scalars.GetFloatData().Data, v)
+		}
+	case schemapb.DataType_Double:
+		var v float64
+		if v, ok = value.(float64); ok {
+			scalars.GetDoubleData().Data = append(scalars.GetDoubleData().Data, v)
+		}
+	case schemapb.DataType_String, schemapb.DataType_VarChar:
+		var v string
+		if v, ok = value.(string); ok {
+			scalars.GetStringData().Data = append(scalars.GetStringData().Data, v)
+		}
+	case schemapb.DataType_JSON:
+		var v []byte
+		if v, ok = value.([]byte); ok {
+			scalars.GetJsonData().Data = append(scalars.GetJsonData().Data, v)
+		}
+	case schemapb.DataType_Array:
+		var v *schemapb.ScalarField
+		if v, ok = value.(*schemapb.ScalarField); ok {
+			scalars.GetArrayData().Data = append(scalars.GetArrayData().Data, v)
+		}
+	case schemapb.DataType_FloatVector:
+		var v []float32
+		if v, ok = value.([]float32); ok {
+			vectors.GetFloatVector().Data = append(vectors.GetFloatVector().Data, v...)
+		}
+	case schemapb.DataType_BinaryVector:
+		var v []byte
+		if v, ok = value.([]byte); ok {
+			vectors.Data = &schemapb.VectorField_BinaryVector{BinaryVector: append(vectors.GetBinaryVector(), v...)}
+		}
+	case schemapb.DataType_Float16Vector:
+		var v []byte
+		if v, ok = value.([]byte); ok {
+			vectors.Data = &schemapb.VectorField_Float16Vector{Float16Vector: append(vectors.GetFloat16Vector(), v...)}
+		}
+	case schemapb.DataType_BFloat16Vector:
+		var v []byte
+		if v, ok = value.([]byte); ok {
+			vectors.Data = &schemapb.VectorField_Bfloat16Vector{Bfloat16Vector: append(vectors.GetBfloat16Vector(), v...)}
+		}
+	default:
+		ok = false
+	}
+	if !ok {
+		return merr.WrapErrImportFailed(fmt.Sprintf("unexpected value type '%T' for field '%s' of type '%s'",
+			value, fieldData.GetFieldName(), fieldData.GetType().String()))
+	}
+	return nil
+}
+
+// fieldDataSink accumulates the values into a FieldData for each field, the fields are
+// decided by the first row.
+type fieldDataSink struct {
+	r          *rowParser
+	fieldIDs   []int64
+	fieldsData map[int64]*schemapb.FieldData
+	numRows    int
+	appended   int
+}
+
+func (s *fieldDataSink) Append(fieldID int64, value any) error {
+	if fieldID == DeletedFieldID {
+		return merr.WrapErrImportFailed("the row is a deletion, which cannot be converted to field data")
+	}
+	fieldData, ok := s.fieldsData[fieldID]
+	if !ok {
+		if s.numRows > 0 {
+			return merr.WrapErrImportFailed(fmt.Sprintf("field '%s' is not provided by the previous rows", s.r.id2Field[fieldID].GetName()))
+		}
+		fieldData = s.r.newFieldData(s.r.id2Field[fieldID])
+		s.fieldsData[fieldID] = fieldData
+		s.fieldIDs = append(s.fieldIDs, fieldID)
+	}
+	s.appended++
+	return appendFieldData(fieldData, value)
+}
+
+func (s *fieldDataSink) EndRow() error {
+	if s.appended != len(s.fieldIDs) {
+		return merr.WrapErrImportFailed(fmt.Sprintf("the row has %d fields, but the previous rows have %d fields", s.appended, len(s.fieldIDs)))
+	}
+	s.numRows++
+	s.appended = 0
+	return nil
+}
+
+// DiscardRow does nothing, the field data is dropped as a whole if any row is invalid.
+func (s *fieldDataSink) DiscardRow() {}
+
+// ParseToFieldData parses the rows and accumulates the values into a FieldData for each field,
+// ordered by the field id, which can be used by the insertion directly. The values are appended
+// to the FieldData as they are parsed, without building a Row for every row. The fields are
+// decided by the first row, parsing stops at the first invalid row. The delete marker rows are
+// not supported.
+func (r *rowParser) ParseToFieldData(raws []any) ([]*schemapb.FieldData, error) {
+	sink := &fieldDataSink{r: r, fieldsData: make(map[int64]*schemapb.FieldData)}
+	if err := r.ParseToSink(raws, sink); err != nil {
+		return nil, err
+	}
+	sort.Slice(sink.fieldIDs, func(i, j int) bool { return sink.fieldIDs[i] < sink.fieldIDs[j] })
+	return lo.Map(sink.fieldIDs, func(fieldID int64, _ int) *schemapb.FieldData {
+		return sink.fieldsData[fieldID]
+	}), nil
+}
@@ -0,0 +1,108 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+)
+
+func TestRowParser_ParseToFieldData(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "bool", DataType: schemapb.DataType_Bool},
+		&schemapb.FieldSchema{FieldID: 103, Name: "int8", DataType: schemapb.DataType_Int8},
+		&schemapb.FieldSchema{FieldID: 104, Name: "int16", DataType: schemapb.DataType_Int16},
+		&schemapb.FieldSchema{FieldID: 105, Name: "int32", DataType: schemapb.DataType_Int32},
+		&schemapb.FieldSchema{FieldID: 106, Name: "float", DataType: schemapb.DataType_Float},
+		&schemapb.FieldSchema{FieldID: 107, Name: "double", DataType: schemapb.DataType_Double},
+		&schemapb.FieldSchema{FieldID: 108, Name: "varchar", DataType: schemapb.DataType_VarChar},
+		&schemapb.FieldSchema{FieldID: 109, Name: "json", DataType: schemapb.DataType_JSON},
+		&schemapb.FieldSchema{FieldID: 110, Name: "array", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_Int64},
+		&schemapb.FieldSchema{
+			FieldID:    111,
+			Name:       "bin",
+			DataType:   schemapb.DataType_BinaryVector,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "8"}},
+		},
+		&schemapb.FieldSchema{
+			FieldID:    112,
+			Name:       "fp16",
+			DataType:   schemapb.DataType_Float16Vector,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "1"}},
+		},
+		&schemapb.FieldSchema{FieldID: 113, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+	)
+	parser, err := NewRowParser(schema)
+	assert.NoError(t, err)
+
+	fieldsData, err := parser.ParseToFieldData(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2], "bool": true, "int8": 1, "int16": 2, "int32": 3, "float": 0.5, "double": 1.5,
+		"varchar": "a", "json": {"a": 1}, "array": [1, 2], "bin": [255], "fp16": [1, 2], "x": 1}`,
+		`{"id": 2, "vector": [0.3, 0.4], "bool": false, "int8": -1, "int16": -2, "int32": -3, "float": 1.5, "double": 2.5,
+		"varchar": "b", "json": "{}", "array": [], "bin": [0], "fp16": [3, 4]}`,
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, 14, len(fieldsData))
+	for i, fieldData := range fieldsData {
+		assert.Equal(t, int64(100+i), fieldData.GetFieldId())
+	}
+	assert.Equal(t, "id", fieldsData[0].GetFieldName())
+	assert.Equal(t, []int64{1, 2}, fieldsData[0].GetScalars().GetLongData().GetData())
+	assert.Equal(t, int64(2), fieldsData[1].GetVectors().GetDim())
+	assert.Equal(t, []float32{0.1, 0.2, 0.3, 0.4}, fieldsData[1].GetVectors().GetFloatVector().GetData())
+	assert.Equal(t, []bool{true, false}, fieldsData[2].GetScalars().GetBoolData().GetData())
+	assert.Equal(t, []int32{1, -1}, fieldsData[3].GetScalars().GetIntData().GetData())
+	assert.Equal(t, []int32{2, -2}, fieldsData[4].GetScalars().GetIntData().GetData())
+	assert.Equal(t, []int32{3, -3}, fieldsData[5].GetScalars().GetIntData().GetData())
+	assert.Equal(t, []float32{0.5, 1.5}, fieldsData[6].GetScalars().GetFloatData().GetData())
+	assert.Equal(t, []float64{1.5, 2.5}, fieldsData[7].GetScalars().GetDoubleData().GetData())
+	assert.Equal(t, []string{"a", "b"}, fieldsData[8].GetScalars().GetStringData().GetData())
+	assert.Equal(t, [][]byte{[]byte(`{"a":1}`), []byte(`{}`)}, fieldsData[9].GetScalars().GetJsonData().GetData())
+	arrays := fieldsData[10].GetScalars().GetArrayData()
+	assert.Equal(t, schemapb.DataType_Int64, arrays.GetElementType())
+	assert.Equal(t, 2, len(arrays.GetData()))
+	assert.Equal(t, []int64{1, 2}, arrays.GetData()[0].GetLongData().GetData())
+	assert.Equal(t, []byte{255, 0}, fieldsData[11].GetVectors().GetBinaryVector())
+	assert.Equal(t, int64(8), fieldsData[11].GetVectors().GetDim())
+	assert.Equal(t, []byte{1, 2, 3, 4}, fieldsData[12].GetVectors().GetFloat16Vector())
+	assert.True(t, fieldsData[13].GetIsDynamic())
+	assert.Equal(t, [][]byte{[]byte(`{"x":1}`), []byte(`{}`)}, fieldsData[13].GetScalars().GetJsonData().GetData())
+
+	_, err = parser.ParseToFieldData(decodeRows(t, `{"id": 1}`))
+	assert.ErrorContains(t, err, "failed to parse row 0")
+
+	// the auto-generated primary key is not provided
+	schema = newTestSchema()
+	schema.Fields[0].AutoID = true
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	fieldsData, err = parser.ParseToFieldData(decodeRows(t, `{"vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(fieldsData))
+	assert.Equal(t, schemapb.DataType_FloatVector, fieldsData[0].GetType())
+
+	parser, err = NewRowParser(newTestSchema(), WithDeleteMarker("_deleted"))
+	assert.NoError(t, err)
+	_, err = parser.ParseToFieldData(decodeRows(t, `{"id": 1, "_deleted": true}`))
+	assert.ErrorContains(t, err, "failed to parse row 0")
+	assert.ErrorContains(t, err, "the row is a deletion, which cannot be converted to field data")
+}
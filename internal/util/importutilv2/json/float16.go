@@ -0,0 +1,123 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func (r *rowParser) checkHalfFloatFields() error {
+	for fieldID := range r.option.halfFloatFields {
+		dataType := r.id2Field[fieldID].GetDataType()
+		if dataType != schemapb.DataType_Float16Vector && dataType != schemapb.DataType_BFloat16Vector {
+			return merr.WrapErrImportFailed(fmt.Sprintf("float conversion is only supported for Float16Vector and BFloat16Vector field, field id: %d", fieldID))
+		}
+	}
+	return nil
+}
+
+// floatsToHalfVector converts the float array of the Float16Vector or BFloat16Vector field to
+// the little-endian half-precision bytes. The floats are parsed as float64 and rounded to the
+// nearest half-precision value once, ties to even, and the floats out of the half-precision
+// range, which would be infinity, are rejected.
+func (r *rowParser) floatsToHalfVector(arr []any, fieldID int64) ([]byte, error) {
+	field := r.id2Field[fieldID]
+	convert := float64ToFloat16
+	if field.GetDataType() == schemapb.DataType_BFloat16Vector {
+		convert = float64ToBFloat16
+	}
+	vec := make([]byte, 2*len(arr))
+	for i, elem := range arr {
+		value, ok := r.asNumber(elem)
+		if !ok {
+			return nil, r.wrapTypeError(elem, fieldID)
+		}
+		num, err := strconv.ParseFloat(value.String(), 64)
+		if err != nil {
+			return nil, newParseError(ErrKindInvalidValue, field, value, merr.WrapErrImportFailed(
+				fmt.Sprintf("failed to parse component %s at index %d of field '%s', error: %v", value, i, field.GetName(), err)))
+		}
+		half, ok := convert(num)
+		if !ok {
+			return nil, newParseError(ErrKindInvalidValue, field, value, merr.WrapErrImportFailed(
+				fmt.Sprintf("component %s at index %d of field '%s' overflows %s", value, i, field.GetName(), field.GetDataType().String())))
+		}
+		binary.LittleEndian.PutUint16(vec[i*2:], half)
+	}
+	return vec, nil
+}
+
+// float64ToFloat16 converts the float to IEEE 754 half precision, rounding to nearest, ties to even.
+// It returns false if the finite float overflows to infinity.
+func float64ToFloat16(f float64) (uint16, bool) {
+	return float64ToHalf(f, 5, 10)
+}
+
+// float64ToBFloat16 converts the float to bfloat16, rounding to nearest, ties to even.
+// It returns false if the finite float overflows to infinity.
+func float64ToBFloat16(f float64) (uint16, bool) {
+	return float64ToHalf(f, 8, 7)
+}
+
+// float64ToHalf converts the float to the 16-bit format with the given exponent and mantissa bits,
+// the mantissa is rounded directly from the float64, so the result is rounded only once.
+func float64ToHalf(f float64, expBits, mantBits uint) (uint16, bool) {
+	bits := math.Float64bits(f)
+	sign := uint16(bits>>48) & 0x8000
+	exp := int(bits>>52) & 0x7ff
+	mant := bits & (1<<52 - 1)
+	maxExp := 1<<expBits - 1
+	inf := uint64(maxExp) << mantBits
+	if exp == 0x7ff {
+		if mant != 0 {
+			// quiet NaN
+			return sign | uint16(inf|1<<(mantBits-1)), true
+		}
+		return sign | uint16(inf), true
+	}
+	// the exponent rebiased from 1023
+	e := exp - 1023 + maxExp>>1
+	if e >= maxExp {
+		return sign | uint16(inf), false
+	}
+	var half uint64
+	shift := 52 - mantBits
+	if e <= 0 {
+		// less than half of the smallest subnormal, rounded to zero
+		if e < -int(mantBits) {
+			return sign, true
+		}
+		// subnormal, the implicit leading bit is kept in the mantissa
+		mant |= 1 << 52
+		shift += uint(1 - e)
+	} else {
+		half = uint64(e) << mantBits
+	}
+	half |= mant >> shift
+	rem, halfway := mant&(1<<shift-1), uint64(1)<<(shift-1)
+	if rem > halfway || (rem == halfway && half&1 == 1) {
+		// the carry may round up to the next exponent, or to infinity
+		half++
+	}
+	return sign | uint16(half), half < inf
+}
@@ -0,0 +1,153 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+)
+
+func TestFloat64ToFloat16(t *testing.T) {
+	cases := []struct {
+		value    float64
+		expected uint16
+		ok       bool
+	}{
+		{1, 0x3c00, true},
+		{-2, 0xc000, true},
+		{0.1, 0x2e66, true},
+		{65504, 0x7bff, true},
+		{65519, 0x7bff, true},
+		// ties to even
+		{1 + 1.0/2048, 0x3c00, true},
+		{1 + 3.0/2048, 0x3c02, true},
+		// subnormals
+		{math.Ldexp(1, -24), 0x0001, true},
+		{math.Ldexp(1, -25), 0x0000, true},
+		{math.Ldexp(3, -25), 0x0002, true},
+		{math.Ldexp(1, -30), 0x0000, true},
+		{math.Ldexp(1023, -24), 0x03ff, true},
+		{math.Ldexp(2047, -25), 0x0400, true},
+		// rounded once, a float32 would round it to the tie first
+		{1 + math.Ldexp(1, -11) + math.Ldexp(1, -40), 0x3c01, true},
+		{math.NaN(), 0x7e00, true},
+		{math.Inf(-1), 0xfc00, true},
+		// overflow, directly or by rounding
+		{65520, 0x7c00, false},
+		{-1e6, 0xfc00, false},
+	}
+	for _, c := range cases {
+		half, ok := float64ToFloat16(c.value)
+		assert.Equal(t, c.expected, half, "value %v", c.value)
+		assert.Equal(t, c.ok, ok, "value %v", c.value)
+	}
+}
+
+func TestFloat64ToBFloat16(t *testing.T) {
+	cases := []struct {
+		value    float64
+		expected uint16
+		ok       bool
+	}{
+		{1, 0x3f80, true},
+		{-2, 0xc000, true},
+		{0.1, 0x3dcd, true},
+		// ties to even
+		{1 + 1.0/256, 0x3f80, true},
+		{1 + 3.0/256, 0x3f82, true},
+		{1 + math.Ldexp(1, -8) + math.Ldexp(1, -30), 0x3f81, true},
+		{math.Ldexp(1, -133), 0x0001, true},
+		{math.Ldexp(1, -134), 0x0000, true},
+		{math.MaxFloat32, 0x7f80, false},
+		{1e39, 0x7f80, false},
+		{math.NaN(), 0x7fc0, true},
+	}
+	for _, c := range cases {
+		half, ok := float64ToBFloat16(c.value)
+		assert.Equal(t, c.expected, half, "value %v", c.value)
+		assert.Equal(t, c.ok, ok, "value %v", c.value)
+	}
+}
+
+func TestRowParser_HalfFloatConversion(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{
+			FieldID:    102,
+			Name:       "fp16",
+			DataType:   schemapb.DataType_Float16Vector,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}},
+		},
+		&schemapb.FieldSchema{
+			FieldID:    103,
+			Name:       "bf16",
+			DataType:   schemapb.DataType_BFloat16Vector,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}},
+		},
+	)
+	parser, err := NewRowParser(schema, WithHalfFloatConversion(102, 103))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "fp16": [1, -2], "bf16": [1, 0.1]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x3c, 0x00, 0xc0}, row[102])
+	assert.Equal(t, []byte{0x80, 0x3f, 0xcd, 0x3d}, row[103])
+
+	// the byte form is still accepted
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "fp16": [0, 60, 0, 192], "bf16": [128, 63, 205, 61]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x3c, 0x00, 0xc0}, row[102])
+	assert.Equal(t, []byte{0x80, 0x3f, 0xcd, 0x3d}, row[103])
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "fp16": [70000, 1], "bf16": [1, 0.1]}`))
+	assert.ErrorContains(t, err, "component 70000 at index 0 of field 'fp16' overflows Float16Vector")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "fp16": [1, 1], "bf16": [1, 1e39]}`))
+	assert.ErrorContains(t, err, "component 1e39 at index 1 of field 'bf16' overflows BFloat16Vector")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "fp16": [1, 1e400], "bf16": [1, 0.1]}`))
+	assert.ErrorContains(t, err, "failed to parse component 1e400 at index 1 of field 'fp16'")
+	// rounded once from the float64
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "fp16": [1.0004882812509094947017729282379150390625, 1], "bf16": [1, 0.1]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x3c, 0x00, 0x3c}, row[102])
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "fp16": [1, 2, 3], "bf16": [1, 0.1]}`))
+	assert.ErrorContains(t, err, "expected dim")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "fp16": [1, "a"], "bf16": [1, 0.1]}`))
+	assert.Error(t, err)
+
+	// the converted floats are observed one per component by the batch and the equal dim checks
+	parser, err = NewRowParser(schema, WithHalfFloatConversion(102, 103), WithBatchDimCheck(), WithEqualDim(101, 102, 103))
+	assert.NoError(t, err)
+	rows, err := parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2], "fp16": [1, -2], "bf16": [1, 0.1]}`,
+		`{"id": 2, "vector": [0.1, 0.2], "fp16": [1, -2], "bf16": [128, 63, 205, 61]}`,
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+	_, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2], "fp16": [1, -2], "bf16": [1, 0.1]}`,
+		`{"id": 2, "vector": [0.1, 0.2], "fp16": [1, -2, 3], "bf16": [1, 0.1]}`,
+	))
+	assert.ErrorContains(t, err, "failed to parse row 1")
+
+	_, err = NewRowParser(schema, WithHalfFloatConversion(101))
+	assert.ErrorContains(t, err, "float conversion is only supported for Float16Vector and BFloat16Vector field")
+}
@@ -0,0 +1,61 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// HashAlgorithm is the algorithm used to hash VarChar values at import.
+type HashAlgorithm string
+
+const (
+	HashMD5    HashAlgorithm = "md5"
+	HashSHA1   HashAlgorithm = "sha1"
+	HashSHA256 HashAlgorithm = "sha256"
+	HashSHA512 HashAlgorithm = "sha512"
+)
+
+func newHashFunc(algorithm HashAlgorithm) (func() hash.Hash, error) {
+	switch algorithm {
+	case HashMD5:
+		return md5.New, nil
+	case HashSHA1:
+		return sha1.New, nil
+	case HashSHA256:
+		return sha256.New, nil
+	case HashSHA512:
+		return sha512.New, nil
+	default:
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("unsupported hash algorithm '%s'", algorithm))
+	}
+}
+
+// hashString returns the hex encoded digest of the value.
+func hashString(newHash func() hash.Hash, value string) string {
+	h := newHash()
+	h.Write([]byte(value))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,88 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"fmt"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// MultiSchemaParser parses rows of several collections interleaved in one file,
+// each row carries a tag which names the schema it belongs to.
+type MultiSchemaParser struct {
+	schemaKey string
+	parsers   map[string]RowParser
+}
+
+// NewMultiSchemaParser creates the parsers of the schemas keyed by their tags, the options
+// are keyed by the tags too, since most options refer to the fields by id, which are
+// usually reused by the schemas of different collections.
+func NewMultiSchemaParser(schemas map[string]*schemapb.CollectionSchema, schemaKey string,
+	opts map[string][]RowParserOption,
+) (*MultiSchemaParser, error) {
+	for tag := range opts {
+		if _, ok := schemas[tag]; !ok {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("the options are given for unknown schema '%s'", tag))
+		}
+	}
+	parsers := make(map[string]RowParser, len(schemas))
+	for tag, schema := range schemas {
+		parser, err := NewRowParser(schema, opts[tag]...)
+		if err != nil {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("failed to create parser for schema '%s', error: %v", tag, err))
+		}
+		parsers[tag] = parser
+	}
+	return &MultiSchemaParser{
+		schemaKey: schemaKey,
+		parsers:   parsers,
+	}, nil
+}
+
+// Parse parses the row with the parser selected by its tag, the tag key is stripped
+// before parsing. It returns the tag together with the parsed row.
+func (m *MultiSchemaParser) Parse(raw any) (string, Row, error) {
+	stringMap, ok := raw.(map[string]any)
+	if !ok {
+		return "", nil, merr.WrapErrImportFailed("invalid JSON format, each row should be a key-value map")
+	}
+	value, ok := stringMap[m.schemaKey]
+	if !ok {
+		return "", nil, merr.WrapErrImportFailed(fmt.Sprintf("the schema key '%s' is missed", m.schemaKey))
+	}
+	tag, ok := value.(string)
+	if !ok {
+		return "", nil, merr.WrapErrImportFailed(fmt.Sprintf("the value of schema key '%s' should be a string, got '%v'", m.schemaKey, value))
+	}
+	parser, ok := m.parsers[tag]
+	if !ok {
+		return "", nil, merr.WrapErrImportFailed(fmt.Sprintf("unknown schema '%s'", tag))
+	}
+	stripped := make(map[string]any, len(stringMap)-1)
+	for k, v := range stringMap {
+		if k != m.schemaKey {
+			stripped[k] = v
+		}
+	}
+	row, err := parser.Parse(stripped)
+	if err != nil {
+		return "", nil, err
+	}
+	return tag, row, nil
+}
@@ -0,0 +1,75 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestMultiSchemaParser(t *testing.T) {
+	schemaA := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "a", DataType: schemapb.DataType_Int32})
+	schemaB := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "b", DataType: schemapb.DataType_VarChar})
+	parser, err := NewMultiSchemaParser(map[string]*schemapb.CollectionSchema{"A": schemaA, "B": schemaB}, "_schema", nil)
+	assert.NoError(t, err)
+
+	tag, row, err := parser.Parse(decodeRow(t, `{"_schema": "A", "id": 1, "vector": [0.1, 0.2], "a": 8}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "A", tag)
+	assert.Equal(t, int32(8), row[102])
+
+	raw := decodeRow(t, `{"_schema": "B", "id": 2, "vector": [0.1, 0.2], "b": "x"}`)
+	tag, row, err = parser.Parse(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, "B", tag)
+	assert.Equal(t, "x", row[102])
+	// the input is not modified
+	assert.Contains(t, raw, "_schema")
+
+	_, _, err = parser.Parse(decodeRow(t, `{"_schema": "C", "id": 1, "vector": [0.1, 0.2]}`))
+	assert.ErrorContains(t, err, "unknown schema 'C'")
+	_, _, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "a": 8}`))
+	assert.ErrorContains(t, err, "schema key '_schema' is missed")
+	_, _, err = parser.Parse(decodeRow(t, `{"_schema": "A", "id": 1, "vector": [0.1, 0.2], "b": "x"}`))
+	assert.ErrorContains(t, err, "the field 'b' is not defined in schema")
+}
+
+func TestMultiSchemaParser_Options(t *testing.T) {
+	schemaA := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "a", DataType: schemapb.DataType_Int32})
+	schemaB := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "b", DataType: schemapb.DataType_Int32})
+	schemas := map[string]*schemapb.CollectionSchema{"A": schemaA, "B": schemaB}
+	parser, err := NewMultiSchemaParser(schemas, "_schema", map[string][]RowParserOption{
+		"A": {WithClampIntegers(nil, 102)},
+	})
+	assert.NoError(t, err)
+
+	// the option of schema A doesn't apply to the field of schema B with the same id
+	_, row, err := parser.Parse(decodeRow(t, `{"_schema": "A", "id": 1, "vector": [0.1, 0.2], "a": 3000000000}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(math.MaxInt32), row[102])
+	_, _, err = parser.Parse(decodeRow(t, `{"_schema": "B", "id": 1, "vector": [0.1, 0.2], "b": 3000000000}`))
+	assert.ErrorContains(t, err, "out of range")
+
+	_, err = NewMultiSchemaParser(schemas, "_schema", map[string][]RowParserOption{"C": {WithIgnoreKeys("x")}})
+	assert.ErrorContains(t, err, "the options are given for unknown schema 'C'")
+	_, err = NewMultiSchemaParser(schemas, "_schema", map[string][]RowParserOption{"B": {WithClampIntegers(nil, 100)}})
+	assert.ErrorContains(t, err, "failed to create parser for schema 'B'")
+}
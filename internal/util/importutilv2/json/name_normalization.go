@@ -0,0 +1,122 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// NameNormalization is the mode to match the keys of rows to the field names in schema.
+type NameNormalization int
+
+const (
+	// NameNormalizationNone matches the keys to the field names exactly.
+	NameNormalizationNone NameNormalization = iota
+	// NameNormalizationSnakeCamel matches the keys to the field names by their snake_case form,
+	// e.g. the keys "userId", "userID" and "UserId" match the field "user_id", and vice versa.
+	NameNormalizationSnakeCamel
+)
+
+// toSnakeCase converts the name to snake_case, e.g. "userId" and "UserID" to "user_id".
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, c := range runes {
+		if unicode.IsUpper(c) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				sb.WriteRune('_')
+			}
+			c = unicode.ToLower(c)
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+// toCamelCase converts the name to camelCase, e.g. "user_id" to "userId".
+func toCamelCase(name string) string {
+	parts := strings.Split(name, "_")
+	var sb strings.Builder
+	for i, part := range parts {
+		if i == 0 || part == "" {
+			sb.WriteString(part)
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		sb.WriteString(string(runes))
+	}
+	return sb.String()
+}
+
+// initNameNormalization maps the snake_case form of each field name to the field, the keys of
+// rows are normalized the same way when they are looked up. Two fields with the same normalized
+// form cannot be told apart, so they are rejected, and so is an alias normalized to another field.
+func (r *rowParser) initNameNormalization() error {
+	if r.option.nameNormalization == NameNormalizationNone {
+		return nil
+	}
+	names := make([]string, 0, len(r.name2FieldID)+1)
+	for name := range r.name2FieldID {
+		names = append(names, name)
+	}
+	// the auto-generated primary key is normalized too, so that it's detected if provided
+	if r.pkField.GetAutoID() {
+		names = append(names, r.pkField.GetName())
+	}
+	sort.Strings(names)
+	r.snake2Name = make(map[string]string, len(names))
+	for _, name := range names {
+		snake := toSnakeCase(name)
+		if other, ok := r.snake2Name[snake]; ok {
+			return merr.WrapErrImportFailed(fmt.Sprintf("field '%s' and field '%s' collide after name normalization", name, other))
+		}
+		r.snake2Name[snake] = name
+	}
+	aliases := make([]string, 0, len(r.alias2Name))
+	for alias := range r.alias2Name {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		if name, ok := r.snake2Name[toSnakeCase(alias)]; ok && name != r.alias2Name[alias] {
+			return merr.WrapErrImportFailed(fmt.Sprintf("the normalized name '%s' of field '%s' collides with an alias of field '%s'",
+				alias, name, r.alias2Name[alias]))
+		}
+	}
+	return nil
+}
+
+// aliasOf returns the name of the field which the key provides by an alias or, with name
+// normalization, by a form other than the field name, e.g. "userID" for the field "user_id".
+func (r *rowParser) aliasOf(key string) (string, bool) {
+	if name, ok := r.alias2Name[key]; ok {
+		return name, true
+	}
+	if r.snake2Name != nil {
+		if name, ok := r.snake2Name[toSnakeCase(key)]; ok && name != key {
+			return name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,99 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestNameNormalization_Convert(t *testing.T) {
+	assert.Equal(t, "user_id", toSnakeCase("userId"))
+	assert.Equal(t, "user_id", toSnakeCase("UserID"))
+	assert.Equal(t, "user_id", toSnakeCase("user_id"))
+	assert.Equal(t, "http_server2_port", toSnakeCase("HTTPServer2Port"))
+	assert.Equal(t, "userId", toCamelCase("user_id"))
+	assert.Equal(t, "userId", toCamelCase("userId"))
+	assert.Equal(t, "httpServer2Port", toCamelCase("http_server2_port"))
+}
+
+func TestRowParser_NameNormalization(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "user_id", DataType: schemapb.DataType_Int64},
+		&schemapb.FieldSchema{FieldID: 103, Name: "createdAt", DataType: schemapb.DataType_Int64},
+		&schemapb.FieldSchema{FieldID: 104, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+	)
+	parser, err := NewRowParser(schema, WithNameNormalization(NameNormalizationSnakeCamel))
+	assert.NoError(t, err)
+
+	// camelCase data against snake_case schema, and vice versa
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "userId": 2, "created_at": 3, "userName": "a"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), row[102])
+	assert.Equal(t, int64(3), row[103])
+	assert.Equal(t, `{"userName":"a"}`, string(row[104].([]byte)))
+
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "user_id": 2, "createdAt": 3}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), row[102])
+	assert.Equal(t, int64(3), row[103])
+
+	// the keys are normalized as well, not only matched to the snake_case and camelCase forms
+	for _, key := range []string{"userID", "UserId", "UserID"} {
+		row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "`+key+`": 2, "CreatedAt": 3}`))
+		assert.NoError(t, err, key)
+		assert.Equal(t, int64(2), row[102], key)
+		assert.Equal(t, int64(3), row[103], key)
+		assert.Equal(t, []byte(`{}`), row[104], key)
+	}
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "user_id": 2, "userId": 2, "createdAt": 3}`))
+	assert.ErrorContains(t, err, "the field 'user_id' is provided more than once by aliases")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "userID": 2, "UserId": 2, "createdAt": 3}`))
+	assert.ErrorContains(t, err, "the field 'user_id' is provided more than once by aliases")
+
+	// the keys are matched exactly without normalization
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "userId": 2, "createdAt": 3}`))
+	assert.ErrorContains(t, err, "value of field 'user_id' is missed")
+}
+
+func TestRowParser_NameNormalizationCollision(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "user_id", DataType: schemapb.DataType_Int64},
+		&schemapb.FieldSchema{FieldID: 103, Name: "userId", DataType: schemapb.DataType_Int64},
+	)
+	_, err := NewRowParser(schema, WithNameNormalization(NameNormalizationSnakeCamel))
+	assert.ErrorContains(t, err, "field 'user_id' and field 'userId' collide after name normalization")
+
+	schema = newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "user_id", DataType: schemapb.DataType_Int64},
+		&schemapb.FieldSchema{FieldID: 103, Name: "owner", DataType: schemapb.DataType_Int64},
+	)
+	_, err = NewRowParser(schema, WithNameNormalization(NameNormalizationSnakeCamel),
+		WithAliases(map[string][]string{"owner": {"userId"}}))
+	assert.ErrorContains(t, err, "the normalized name 'userId' of field 'user_id' collides with an alias of field 'owner'")
+
+	// an explicit alias equal to the normalized name is fine
+	_, err = NewRowParser(schema, WithNameNormalization(NameNormalizationSnakeCamel),
+		WithAliases(map[string][]string{"user_id": {"userId"}}))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,93 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// asNumber returns the numeric value as a json.Number. Besides json.Number, which is
+// decoded with UseNumber, it accepts the Go numbers decoded without UseNumber or built
+// by the callers, and the numeric strings if WithNumericStrings is set.
+// An integral float64 within the exact range is written as an integer, so that it can
+// be parsed by the integer fields, note the float fields count it as an integer as well.
+func (r *rowParser) asNumber(obj any) (json.Number, bool) {
+	switch v := obj.(type) {
+	case json.Number:
+		return v, true
+	case float64:
+		return floatToNumber(v)
+	case float32:
+		return floatToNumber(float64(v))
+	case int:
+		return json.Number(strconv.Itoa(v)), true
+	case int64:
+		return json.Number(strconv.FormatInt(v, 10)), true
+	case int32:
+		return json.Number(strconv.FormatInt(int64(v), 10)), true
+	case string:
+		// json.Valid rejects the forms which are not JSON numbers, such as "NaN" and "0x10"
+		if !r.option.numericStrings || !json.Valid([]byte(v)) {
+			return "", false
+		}
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return "", false
+		}
+		return json.Number(v), true
+	default:
+		return "", false
+	}
+}
+
+func floatToNumber(f float64) (json.Number, bool) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", false
+	}
+	if f == math.Trunc(f) && f >= -(1<<53) && f <= 1<<53 {
+		return json.Number(strconv.FormatInt(int64(f), 10)), true
+	}
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), true
+}
+
+// parsePercentage parses the percentage string of the field. The division is done on the exact
+// decimal, so that "7.3%" is 0.073 rather than 7.3/100 in floating point.
+func (r *rowParser) parsePercentage(str string, fieldID int64, divide bool) (json.Number, error) {
+	value := strings.TrimSuffix(str, "%")
+	// json.Valid rejects the forms which are not JSON numbers, such as "NaN" and "0x1p-2"
+	if _, err := strconv.ParseFloat(value, 64); err != nil || !json.Valid([]byte(value)) {
+		return "", newParseError(ErrKindInvalidValue, r.id2Field[fieldID], str, merr.WrapErrImportFailed(
+			fmt.Sprintf("invalid percentage '%s' for field '%s'", str, r.id2Field[fieldID].GetName())))
+	}
+	if !divide {
+		return json.Number(value), nil
+	}
+	rat, _ := new(big.Rat).SetString(value)
+	f, _ := rat.Quo(rat, big.NewRat(100, 1)).Float64()
+	num, ok := floatToNumber(f)
+	if !ok {
+		return "", newParseError(ErrKindInvalidValue, r.id2Field[fieldID], str, merr.WrapErrImportFailed(
+			fmt.Sprintf("invalid percentage '%s' for field '%s'", str, r.id2Field[fieldID].GetName())))
+	}
+	return num, nil
+}
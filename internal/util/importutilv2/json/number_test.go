@@ -0,0 +1,145 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestRowParser_AsNumber(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema(), WithNumericStrings())
+	assert.NoError(t, err)
+	r := parser.(*rowParser)
+
+	for _, c := range []struct {
+		obj    any
+		expect json.Number
+		ok     bool
+	}{
+		{json.Number("1.50"), "1.50", true},
+		{float64(3), "3", true},
+		{float64(1e6), "1000000", true},
+		{float64(0.25), "0.25", true},
+		{float64(1e20), "1e+20", true},
+		{float32(0.5), "0.5", true},
+		{int(-7), "-7", true},
+		{int64(math.MaxInt64), "9223372036854775807", true},
+		{int32(8), "8", true},
+		{"42", "42", true},
+		{"-1.5e3", "-1.5e3", true},
+		{math.NaN(), "", false},
+		{math.Inf(1), "", false},
+		{"NaN", "", false},
+		{"0x10", "", false},
+		{" 1", "", false},
+		{true, "", false},
+		{nil, "", false},
+	} {
+		num, ok := r.asNumber(c.obj)
+		assert.Equal(t, c.ok, ok, c.obj)
+		assert.Equal(t, c.expect, num, c.obj)
+	}
+
+	// strings are not numbers by default
+	parser, err = NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	_, ok := parser.(*rowParser).asNumber("42")
+	assert.False(t, ok)
+}
+
+func TestRowParser_GoNumbers(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "i32", DataType: schemapb.DataType_Int32},
+		&schemapb.FieldSchema{FieldID: 103, Name: "f", DataType: schemapb.DataType_Float},
+		&schemapb.FieldSchema{FieldID: 104, Name: "d", DataType: schemapb.DataType_Double},
+		&schemapb.FieldSchema{FieldID: 105, Name: "arr", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_Int64},
+	)
+	parser, err := NewRowParser(schema)
+	assert.NoError(t, err)
+
+	// the values decoded without UseNumber
+	var raw any
+	err = json.Unmarshal([]byte(`{"id": 1, "vector": [0.1, 2], "i32": 3, "f": 0.5, "d": 1e3, "arr": [1, 2]}`), &raw)
+	assert.NoError(t, err)
+	row, err := parser.Parse(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), row[100])
+	assert.Equal(t, []float32{0.1, 2}, row[101])
+	assert.Equal(t, int32(3), row[102])
+	assert.Equal(t, float32(0.5), row[103])
+	assert.Equal(t, float64(1000), row[104])
+	assert.Equal(t, []int64{1, 2}, row[105].(*schemapb.ScalarField).GetLongData().GetData())
+
+	// the values built by the callers
+	row, err = parser.Parse(map[string]any{"id": int64(2), "vector": []any{float32(0.5), 1}, "i32": 4, "f": 1, "d": 2.5, "arr": []any{int64(3)}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), row[100])
+	assert.Equal(t, []float32{0.5, 1}, row[101])
+
+	_, err = parser.Parse(map[string]any{"id": 1.5, "vector": []any{0.1, 0.2}, "i32": 4, "f": 1, "d": 2.5, "arr": []any{}})
+	assert.Error(t, err)
+	_, err = parser.Parse(map[string]any{"id": 1, "vector": []any{0.1, 0.2}, "i32": "4", "f": 1, "d": 2.5, "arr": []any{}})
+	assert.ErrorContains(t, err, "expected type 'Int32' for field 'i32'")
+
+	parser, err = NewRowParser(schema, WithNumericStrings())
+	assert.NoError(t, err)
+	row, err = parser.Parse(decodeRow(t, `{"id": "1", "vector": ["0.1", 0.2], "i32": "-3", "f": "0.5", "d": "1e3", "arr": ["7"]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), row[100])
+	assert.Equal(t, int32(-3), row[102])
+	assert.Equal(t, []int64{7}, row[105].(*schemapb.ScalarField).GetLongData().GetData())
+	_, err = parser.Parse(decodeRow(t, `{"id": "one", "vector": [0.1, 0.2], "i32": 3, "f": 0.5, "d": 1, "arr": []}`))
+	assert.ErrorContains(t, err, "expected type 'Int64' for field 'id'")
+}
+
+func TestRowParser_Percentage(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "ratio", DataType: schemapb.DataType_Double},
+		&schemapb.FieldSchema{FieldID: 103, Name: "score", DataType: schemapb.DataType_Float},
+	)
+	parser, err := NewRowParser(schema, WithPercentage(102, true), WithPercentage(103, false))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "ratio": "7.3%", "score": "45%"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.073, row[102])
+	assert.Equal(t, float32(45), row[103])
+
+	// the values without '%' are parsed as usual
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "ratio": 0.5, "score": 0.5}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, row[102])
+	assert.Equal(t, float32(0.5), row[103])
+
+	for _, value := range []string{`"%"`, `"abc%"`, `"45 %"`, `"NaN%"`} {
+		_, err = parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": 1, "vector": [0.1, 0.2], "ratio": %s, "score": 1}`, value)))
+		assert.ErrorContains(t, err, "invalid percentage")
+		assert.ErrorContains(t, err, "for field 'ratio'")
+	}
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "ratio": "45", "score": 1}`))
+	assert.Error(t, err)
+
+	_, err = NewRowParser(schema, WithPercentage(100, true))
+	assert.ErrorContains(t, err, "percentage is only supported for Float and Double field")
+}
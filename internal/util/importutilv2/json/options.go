@@ -0,0 +1,791 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+type RowParserOption func(opt *rowParserOption)
+
+// DynamicMergePolicy decides how to resolve a key which exists in both
+// the provided dynamic field value and the redundant pairs of the row.
+type DynamicMergePolicy int
+
+const (
+	// DynamicMergeError fails the row on conflict.
+	DynamicMergeError DynamicMergePolicy = iota
+	// DynamicMergeLastWins keeps the value of the redundant pair on conflict.
+	DynamicMergeLastWins
+)
+
+// NumericRange is the range of the values of a numeric field, both bounds are inclusive.
+type NumericRange struct {
+	Min float64
+	Max float64
+}
+
+// SentinelPolicy decides how to treat the sentinel components, such as null and "NaN",
+// of the vectors given as index-keyed objects.
+type SentinelPolicy int
+
+const (
+	// SentinelReject fails the row if a component is a sentinel.
+	SentinelReject SentinelPolicy = iota
+	// SentinelFillZero stores 0 for the sentinel components.
+	SentinelFillZero
+)
+
+// IndexConstraint is the requirement of an index on the vector field,
+// a zero bound means no limit.
+type IndexConstraint struct {
+	IndexType string
+	MinDim    int
+	MaxDim    int
+}
+
+type computedField struct {
+	fieldID int64
+	fn      func(Row) (any, error)
+}
+
+type rowParserOption struct {
+	floatVectorFromBytes bool
+	floatVectorByteOrder binary.ByteOrder
+	fieldByteOrders      map[int64]binary.ByteOrder
+	boolAsInteger        bool
+	truthyStrings        typeutil.Set[string]
+	falsyStrings         typeutil.Set[string]
+	arrayFromJSONString  bool
+	dedupArrayFields     typeutil.Set[int64]
+	trimArrayFields      typeutil.Set[int64]
+	dropEmptyArrayFields typeutil.Set[int64]
+	bitfields            map[int64]bitfieldOption
+	integerBase          int
+	decimalSeparator     rune
+	integralDecimals     bool
+	strictIntegerFields  typeutil.Set[int64]
+	clampIntegerFields   typeutil.Set[int64]
+	onClamp              func(fieldName string, value string, clamped int64)
+
+	unwrapSingletonVector bool
+	vectorComponentKeys   map[int64][]string
+	vectorColumns         map[int64][]string
+	vectorContainer       string
+	containerFields       []int64
+	vectorEncodings       map[int64]VectorEncoding
+	indexConstraints      map[int64]IndexConstraint
+	equalDimFields        []int64
+	indexKeyedVectors     typeutil.Set[int64]
+	vectorSentinels       typeutil.Set[string]
+	sentinelPolicy        SentinelPolicy
+
+	acceptIntegerForFloat bool
+	integerForFloatCount  *atomic.Int64
+
+	canonicalDynamicField bool
+	dynamicKeyOrder       []string
+	canonicalJSONFields   bool
+	dynamicMerge          bool
+	dynamicMergePolicy    DynamicMergePolicy
+
+	batchDimCheck        bool
+	batchPKTypeCheck     bool
+	maxCollectedErrors   int
+	requireUniformSchema bool
+	maxRowBytes          int
+	maxFieldsPerRow      int
+
+	maxArrayElements      int
+	maxArrayIncludeVector bool
+
+	emptyDynamicCheck bool
+	warnEmptyDynamic  func(fieldName string)
+
+	warnDoublePrecisionLoss  func(fieldName string, value string, parsed float64)
+	warnIntegerFloatVector   func(fieldName string)
+	warnSimilarKey           func(key string, fieldName string)
+	similarKeyDistance       int
+	integerFloatVectorFields typeutil.Set[int64]
+	varCharHashes            map[int64]HashAlgorithm
+	asciiOnlyFields          typeutil.Set[int64]
+	stripBOM                 bool
+	rejectEmptyStringPK      bool
+	hexStringPK              bool
+	maxVarCharBytes          int
+	numericStrings           bool
+	deleteMarker             string
+	schemaVersionKey         string
+	schemaVersion            string
+	jsonStringFields         typeutil.Set[int64]
+	numericRanges            map[int64]NumericRange
+	componentRanges          map[int64]NumericRange
+	jsonKeyAllowlists        map[int64]typeutil.Set[string]
+	allowedValues            map[int64][]any
+	percentFields            map[int64]bool
+	pipelines                map[int64][]func(any) (any, error)
+	timestampFields          map[int64]timestampOption
+	scaleFields              map[int64]scaleOption
+	ignoreKeys               typeutil.Set[string]
+	dynamicKeyPattern        string
+	requireDynamicValue      bool
+	projectFields            []int64
+	aliases                  map[string][]string
+	nameNormalization        NameNormalization
+	computedFields           []computedField
+	vectorNorms              map[int64]int64
+	halfFloatFields          typeutil.Set[int64]
+
+	logger *zap.Logger
+}
+
+func defaultRowParserOption() *rowParserOption {
+	return &rowParserOption{
+		floatVectorByteOrder:     binary.LittleEndian,
+		fieldByteOrders:          make(map[int64]binary.ByteOrder),
+		integerBase:              0,
+		acceptIntegerForFloat:    true,
+		integerFloatVectorFields: typeutil.NewSet[int64](),
+		strictIntegerFields:      typeutil.NewSet[int64](),
+		clampIntegerFields:       typeutil.NewSet[int64](),
+		truthyStrings:            typeutil.NewSet[string](),
+		falsyStrings:             typeutil.NewSet[string](),
+		varCharHashes:            make(map[int64]HashAlgorithm),
+		asciiOnlyFields:          typeutil.NewSet[int64](),
+		jsonStringFields:         typeutil.NewSet[int64](),
+		numericRanges:            make(map[int64]NumericRange),
+		componentRanges:          make(map[int64]NumericRange),
+		jsonKeyAllowlists:        make(map[int64]typeutil.Set[string]),
+		allowedValues:            make(map[int64][]any),
+		percentFields:            make(map[int64]bool),
+		pipelines:                make(map[int64][]func(any) (any, error)),
+		timestampFields:          make(map[int64]timestampOption),
+		scaleFields:              make(map[int64]scaleOption),
+		vectorComponentKeys:      make(map[int64][]string),
+		vectorColumns:            make(map[int64][]string),
+		vectorEncodings:          make(map[int64]VectorEncoding),
+		indexConstraints:         make(map[int64]IndexConstraint),
+		indexKeyedVectors:        typeutil.NewSet[int64](),
+		vectorSentinels:          typeutil.NewSet[string](),
+		ignoreKeys:               typeutil.NewSet[string](),
+		dedupArrayFields:         typeutil.NewSet[int64](),
+		trimArrayFields:          typeutil.NewSet[int64](),
+		dropEmptyArrayFields:     typeutil.NewSet[int64](),
+		bitfields:                make(map[int64]bitfieldOption),
+		vectorNorms:              make(map[int64]int64),
+		halfFloatFields:          typeutil.NewSet[int64](),
+	}
+}
+
+// WithFloatVectorFromBytes makes FloatVector fields accept the raw float32 bytes,
+// given as an array of 4*dim byte values, and decode them with the given byte order.
+func WithFloatVectorFromBytes(order binary.ByteOrder) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.floatVectorFromBytes = true
+		opt.floatVectorByteOrder = order
+	}
+}
+
+// WithHalfFloatConversion accepts the float arrays of dim elements, in the same form as the
+// FloatVector fields, for the Float16Vector and BFloat16Vector fields, and converts them to the
+// half-precision bytes. The floats are rounded to nearest, ties to even, and the floats which
+// overflow to infinity are rejected. The byte form is still accepted.
+func WithHalfFloatConversion(fieldIDs ...int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.halfFloatFields.Insert(fieldIDs...)
+	}
+}
+
+// WithByteOrder overrides the byte order of the float32 bytes of the FloatVector field, which
+// are given as an array of byte values or as an encoded string, for the producers of different
+// platforms. The bytes are still required to be 4*dim long.
+func WithByteOrder(fieldID int64, order binary.ByteOrder) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.fieldByteOrders[fieldID] = order
+	}
+}
+
+// WithBoolAsInteger makes integer fields accept JSON booleans,
+// true is stored as 1 and false is stored as 0.
+// Booleans are still rejected by non-integer fields.
+func WithBoolAsInteger() RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.boolAsInteger = true
+	}
+}
+
+// WithBoolStrings accepts the strings like "Y" and "N" for Bool fields and Array<Bool> fields,
+// they are matched case-insensitively, other strings are still rejected.
+func WithBoolStrings(truthy []string, falsy []string) RowParserOption {
+	return func(opt *rowParserOption) {
+		for _, str := range truthy {
+			opt.truthyStrings.Insert(strings.ToLower(str))
+		}
+		for _, str := range falsy {
+			opt.falsyStrings.Insert(strings.ToLower(str))
+		}
+	}
+}
+
+// WithIntegralDecimals makes integer fields accept the numbers written with a zero
+// fractional part, such as 1.0 and 2.00, except the fields set by WithStrictIntegers.
+func WithIntegralDecimals() RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.integralDecimals = true
+	}
+}
+
+// WithStrictIntegers makes the integer fields reject any number written with a decimal point.
+func WithStrictIntegers(fieldIDs ...int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.strictIntegerFields.Insert(fieldIDs...)
+	}
+}
+
+// WithClampIntegers clamps the values of the Int8, Int16 and Int32 fields out of the range of
+// the type to its min or max rather than failing the row, onClamp is called for each clamped
+// value if it's not nil.
+func WithClampIntegers(onClamp func(fieldName string, value string, clamped int64), fieldIDs ...int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.clampIntegerFields.Insert(fieldIDs...)
+		opt.onClamp = onClamp
+	}
+}
+
+// WithDecimalSeparator accepts strings like "3,14" for Float and Double fields, the separator
+// is converted to '.' before parsing. A string with more than one separator, or with a '.'
+// while the separator is not '.', is rejected as ambiguous.
+func WithDecimalSeparator(sep rune) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.decimalSeparator = sep
+	}
+}
+
+// WithDecimalIntegers forces integer values to be parsed in base 10, so that a zero-padded
+// value like "010" is 10. By default the base is implied by the prefix as in strconv.ParseInt,
+// where "010" is an octal value and "0x10" is a hexadecimal value.
+func WithDecimalIntegers() RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.integerBase = 10
+	}
+}
+
+// WithUnwrapSingletonVectorArray makes vector fields accept a vector wrapped in
+// an extra array level, e.g. [[0.1, 0.2]]. Outer arrays with more than one element are still rejected.
+func WithUnwrapSingletonVectorArray() RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.unwrapSingletonVector = true
+	}
+}
+
+// WithVectorComponentKeys accepts an object like {"x": 0.1, "y": 0.2} for the float vector field,
+// the values of the keys are read in the given order, all of the keys must be provided.
+func WithVectorComponentKeys(fieldID int64, keys ...string) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.vectorComponentKeys[fieldID] = keys
+	}
+}
+
+// WithVectorColumns assembles the float vector field from the scalar columns of the row,
+// e.g. "v0", "v1", ... for each dimension in the given order. All of the columns must be
+// provided if any of them is, the field can still be provided as a whole instead.
+func WithVectorColumns(fieldID int64, columns ...string) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.vectorColumns[fieldID] = columns
+	}
+}
+
+// WithVectorContainer reads the vector fields from the object under the container key of the row,
+// e.g. {"vectors": {"img": [...], "txt": [...]}}, whose keys are the names of the fields. All of the
+// given vector fields, or all of the vector fields of the schema if none is given, must be provided
+// by the object if the row has the container key.
+func WithVectorContainer(key string, fieldIDs ...int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.vectorContainer = key
+		opt.containerFields = fieldIDs
+	}
+}
+
+// WithIndexConstraint checks the vector field against the index to be built on it,
+// so that an incompatible schema fails at import rather than at index building.
+func WithIndexConstraint(fieldID int64, constraint IndexConstraint) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.indexConstraints[fieldID] = constraint
+	}
+}
+
+// WithEqualDim requires the vector fields to have the same dim, in the schema and in each row,
+// so that a row whose vectors are misaligned is reported as a whole rather than per field.
+func WithEqualDim(fieldIDs ...int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.equalDimFields = fieldIDs
+	}
+}
+
+// WithIndexKeyedVectors accepts an object like {"0": 0.1, "1": 0.2} for the float vector fields,
+// every index in [0, dim) must be provided.
+func WithIndexKeyedVectors(fieldIDs ...int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.indexKeyedVectors.Insert(fieldIDs...)
+	}
+}
+
+// WithVectorSentinels sets how to treat the null components and the components equal to one of the
+// sentinel strings of the index-keyed vectors, they are rejected by default.
+func WithVectorSentinels(policy SentinelPolicy, sentinels ...string) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.sentinelPolicy = policy
+		opt.vectorSentinels.Insert(sentinels...)
+	}
+}
+
+// WithVectorEncoding accepts the vectors of the fields given as base64 or hex encoded
+// strings of their bytes, float32 values are decoded with the byte order of
+// WithFloatVectorFromBytes, little endian by default. VectorEncodingRepr accepts
+// float vectors printed by NumPy instead, e.g. "[0.1 0.2 0.3]".
+func WithVectorEncoding(encoding VectorEncoding, fieldIDs ...int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		for _, fieldID := range fieldIDs {
+			opt.vectorEncodings[fieldID] = encoding
+		}
+	}
+}
+
+// WithAcceptIntegerForFloat decides whether an integer, i.e. a number without decimal
+// point or exponent, is accepted for Float and Double fields, it's accepted by default.
+func WithAcceptIntegerForFloat(accept bool) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.acceptIntegerForFloat = accept
+	}
+}
+
+// WithIntegerForFloatCounter counts the integers accepted for Float and Double fields.
+func WithIntegerForFloatCounter(counter *atomic.Int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.integerForFloatCount = counter
+	}
+}
+
+// WithArrayFromJSONString makes Array fields accept a JSON-encoded string such as "[1, 2, 3]",
+// which is common when a column was encoded twice.
+func WithArrayFromJSONString() RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.arrayFromJSONString = true
+	}
+}
+
+// WithDedupArrayElements drops the duplicated elements of the array fields,
+// the first occurrence of each element is kept, so the order is preserved.
+func WithDedupArrayElements(fieldIDs ...int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.dedupArrayFields.Insert(fieldIDs...)
+	}
+}
+
+// WithRequireDynamicValue rejects the rows which store nothing in the dynamic field,
+// i.e. the rows whose dynamic field would be "{}".
+func WithRequireDynamicValue() RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.requireDynamicValue = true
+	}
+}
+
+// WithDynamicKeyPattern stores only the keys matching the regular expression, e.g. "^attr_",
+// in the dynamic field, the other keys not defined in schema are rejected. The keys of the
+// explicit dynamic field object are not checked.
+func WithDynamicKeyPattern(pattern string) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.dynamicKeyPattern = pattern
+	}
+}
+
+// WithDynamicKeyOrder serializes the keys of the dynamic field in the given order, the keys
+// not in the order follow in alphabetical order. Used with WithCanonicalDynamicField,
+// the dynamic field of semantically identical rows is byte-identical in a fixed layout.
+func WithDynamicKeyOrder(keys ...string) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.dynamicKeyOrder = keys
+	}
+}
+
+// WithCanonicalDynamicField normalizes the numbers in the dynamic field, so that the
+// serialized dynamic field of semantically identical rows is byte-identical.
+func WithCanonicalDynamicField() RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.canonicalDynamicField = true
+	}
+}
+
+// WithCanonicalJSONFields stores the values of JSON fields with sorted keys and normalized
+// numbers, rather than the verbatim bytes, so the same content is always stored the same.
+func WithCanonicalJSONFields() RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.canonicalJSONFields = true
+	}
+}
+
+// WithDynamicFieldMerge allows a row to provide the dynamic field value explicitly, as a JSON
+// object or a JSON string, which is deep merged with the redundant pairs of the row.
+func WithDynamicFieldMerge(policy DynamicMergePolicy) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.dynamicMerge = true
+		opt.dynamicMergePolicy = policy
+	}
+}
+
+// WithWarnDoublePrecisionLoss sets a callback which is fired when the value of a Double field
+// has more precision than float64 can hold, the value is still imported after rounding.
+func WithWarnDoublePrecisionLoss(fn func(fieldName string, value string, parsed float64)) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.warnDoublePrecisionLoss = fn
+	}
+}
+
+// WithWarnIntegerFloatVector sets a callback which is fired when all the components
+// of a FloatVector value are written as integers.
+func WithWarnIntegerFloatVector(fn func(fieldName string)) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.warnIntegerFloatVector = fn
+	}
+}
+
+// WithWarnSimilarDynamicKey sets a callback which is fired when a key stored in the dynamic field
+// is within maxDistance edits of a field name, e.g. "vectr" and "vector", which is likely a typo.
+func WithWarnSimilarDynamicKey(maxDistance int, fn func(key string, fieldName string)) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.similarKeyDistance = maxDistance
+		opt.warnSimilarKey = fn
+	}
+}
+
+// WithIntegerFloatVectorFields marks the FloatVector fields which are expected to receive
+// all-integer vectors, such vectors are treated as floats without the warning.
+func WithIntegerFloatVectorFields(fieldIDs ...int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.integerFloatVectorFields.Insert(fieldIDs...)
+	}
+}
+
+// WithVarCharHash replaces the value of the VarChar field with its hex encoded digest,
+// the digest must fit in the max_length of the field.
+func WithVarCharHash(fieldID int64, algorithm HashAlgorithm) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.varCharHashes[fieldID] = algorithm
+	}
+}
+
+// WithRejectEmptyStringPK rejects the rows whose VarChar primary key is an empty string.
+func WithRejectEmptyStringPK() RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.rejectEmptyStringPK = true
+	}
+}
+
+// WithHexStringPK makes an Int64 primary key accept a quoted hexadecimal string like "0x1a2b".
+// The "0x" prefix is required and the string is always parsed in base 16, so it is not affected
+// by WithDecimalIntegers, which only applies to the numbers.
+func WithHexStringPK() RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.hexStringPK = true
+	}
+}
+
+// WithMaxVarCharBytes limits the length in bytes of the values of all the VarChar fields and the
+// elements of the Array<VarChar> fields, the stricter of n and the max_length of the field applies.
+func WithMaxVarCharBytes(n int) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.maxVarCharBytes = n
+	}
+}
+
+// WithNumericStrings makes the numeric fields and the elements of numeric arrays and vectors
+// accept the numbers given as strings, such as "42" and "0.5", which must be valid JSON numbers.
+func WithNumericStrings() RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.numericStrings = true
+	}
+}
+
+// WithDeleteMarker makes a row whose key is true a deletion, e.g. {"id": 1, "_deleted": true}.
+// Parse returns only the primary key of such a row, check it with IsDeleted. The key is ignored
+// in the other rows, so it's not stored in the dynamic field.
+func WithDeleteMarker(key string) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.deleteMarker = key
+	}
+}
+
+// WithSchemaVersion requires every row to provide the version of the schema which the data is
+// produced against by the key, e.g. {"_schema_version": "3", ...}, and the version to be the
+// expected one. The key is neither a field nor stored in the dynamic field.
+func WithSchemaVersion(key string, expected string) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.schemaVersionKey = key
+		opt.schemaVersion = expected
+	}
+}
+
+// WithStripBOM removes the leading UTF-8 byte order mark of VarChar values.
+func WithStripBOM() RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.stripBOM = true
+	}
+}
+
+// WithJSONStringFields requires the values of the VarChar fields to be valid JSON,
+// the values are still stored as the raw strings.
+func WithJSONStringFields(fieldIDs ...int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.jsonStringFields.Insert(fieldIDs...)
+	}
+}
+
+// WithASCIIOnly rejects the values of the VarChar fields which contain non-ASCII bytes.
+func WithASCIIOnly(fieldIDs ...int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.asciiOnlyFields.Insert(fieldIDs...)
+	}
+}
+
+// WithProjectFields parses and requires only the given fields, the keys of the other
+// fields in schema are ignored.
+func WithProjectFields(fieldIDs ...int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.projectFields = fieldIDs
+	}
+}
+
+// WithNumericRange rejects the values of the integer, Float and Double fields out of the range.
+func WithNumericRange(fieldID int64, min float64, max float64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.numericRanges[fieldID] = NumericRange{Min: min, Max: max}
+	}
+}
+
+// WithPercentage accepts the percentage strings, e.g. "45%", for the Float or Double field,
+// they are stored as 0.45 if divide is true, or as 45 otherwise. The values without the
+// trailing '%' are parsed as usual.
+func WithPercentage(fieldID int64, divide bool) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.percentFields[fieldID] = divide
+	}
+}
+
+// WithAllowedValues requires the values of the VarChar or integer field to be one of the given
+// values, which are strings for the VarChar field and integers for the integer fields.
+func WithAllowedValues(fieldID int64, values ...any) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.allowedValues[fieldID] = values
+	}
+}
+
+// RegisterFieldPipeline transforms the value of the field by the functions in order before it's
+// parsed, e.g. trim, then lowercase, then hash. The stages registered more than once are appended.
+func RegisterFieldPipeline(fieldID int64, fns []func(any) (any, error)) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.pipelines[fieldID] = append(opt.pipelines[fieldID], fns...)
+	}
+}
+
+// WithComponentRange rejects the vectors of the FloatVector field with any component out of the
+// range, e.g. [-1, 1] for the normalized embeddings. Each component is bounded individually.
+func WithComponentRange(fieldID int64, min float64, max float64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.componentRanges[fieldID] = NumericRange{Min: min, Max: max}
+	}
+}
+
+// WithJSONKeyAllowlist requires the values of the JSON field to be objects whose top-level
+// keys are all in the allowlist, the nested keys are not checked.
+func WithJSONKeyAllowlist(fieldID int64, keys ...string) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.jsonKeyAllowlists[fieldID] = typeutil.NewSet(keys...)
+	}
+}
+
+// WithTimestamp accepts the time strings in the layout, time.RFC3339 if it's empty, for the
+// Int64 or Array<Int64> field, and stores them as the epoch time in the given unit.
+func WithTimestamp(fieldID int64, layout string, unit TimeUnit) RowParserOption {
+	return func(opt *rowParserOption) {
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		opt.timestampFields[fieldID] = timestampOption{layout: layout, unit: unit}
+	}
+}
+
+// WithScaleToInt accepts the decimals, given as numbers or strings, for the Int64 field, and stores
+// them multiplied by the factor and rounded in the mode, e.g. "12.34" is stored as 1234 with the
+// factor 100. RoundExact rejects the decimals which cannot be scaled to integers exactly.
+func WithScaleToInt(fieldID int64, factor int64, mode RoundingMode) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.scaleFields[fieldID] = scaleOption{factor: factor, mode: mode}
+	}
+}
+
+// WithIgnoreKeys drops the keys from every row before field matching and dynamic routing,
+// such as metadata keys attached to every record by the exporter.
+func WithIgnoreKeys(keys ...string) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.ignoreKeys.Insert(keys...)
+	}
+}
+
+// WithAliases sets the accepted aliases of the fields, keyed by the field name in schema.
+// An alias cannot be another field name or an alias of another field.
+func WithAliases(aliases map[string][]string) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.aliases = aliases
+	}
+}
+
+// WithNameNormalization matches the keys of rows to the field names in the normalized form,
+// without listing every alias, see NameNormalization.
+func WithNameNormalization(mode NameNormalization) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.nameNormalization = mode
+	}
+}
+
+// WithComputedField populates the field with the value computed from the parsed row,
+// the value must be in the same form as the JSON input, e.g. json.Number for numeric fields.
+// Computed fields are not required, nor allowed, in the input.
+func WithComputedField(fieldID int64, fn func(Row) (any, error)) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.computedFields = append(opt.computedFields, computedField{fieldID: fieldID, fn: fn})
+	}
+}
+
+// WithVectorNorm populates the Float or Double field with the L2 norm of the FloatVector field,
+// which is computed from the vector as parsed. The norm field is computed, it's not required,
+// nor allowed, in the input.
+func WithVectorNorm(vectorFieldID int64, normFieldID int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.vectorNorms[normFieldID] = vectorFieldID
+		opt.computedFields = append(opt.computedFields, computedField{
+			fieldID: normFieldID,
+			fn: func(row Row) (any, error) {
+				return vectorNorm(row[vectorFieldID])
+			},
+		})
+	}
+}
+
+// WithTrimArrayElements trims the leading and trailing whitespaces of the elements of the Array<VarChar> fields.
+func WithTrimArrayElements(fieldIDs ...int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.trimArrayFields.Insert(fieldIDs...)
+	}
+}
+
+// WithDropEmptyArrayElements drops the empty elements of the Array<VarChar> fields, after they are
+// trimmed if WithTrimArrayElements is set too. The max_capacity is checked after dropping.
+func WithDropEmptyArrayElements(fieldIDs ...int64) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.dropEmptyArrayFields.Insert(fieldIDs...)
+	}
+}
+
+// WithBoolBitfield makes the Array<Bool> field accept an integer which is unpacked into
+// length booleans in the given bit order, e.g. 5 is [true, false, true, false] with length 4
+// and BitOrderLSBFirst. The integers with bits set beyond the length are rejected.
+func WithBoolBitfield(fieldID int64, length int, order BitOrder) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.bitfields[fieldID] = bitfieldOption{length: length, order: order}
+	}
+}
+
+// WithMaxFieldsPerRow rejects the rows with more than n keys, including the keys of the fields
+// and the keys stored in the dynamic field, before any value is parsed.
+func WithMaxFieldsPerRow(n int) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.maxFieldsPerRow = n
+	}
+}
+
+// WithMaxRowBytes rejects the rows whose estimated size, see EstimateRowSize, exceeds the limit.
+func WithMaxRowBytes(n int) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.maxRowBytes = n
+	}
+}
+
+// WithMaxArrayElements rejects the arrays of Array fields with more than n elements,
+// and the arrays of vector fields as well if includeVector is true. It's checked before
+// the elements are converted, regardless of the max_capacity of the fields.
+func WithMaxArrayElements(n int, includeVector bool) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.maxArrayElements = n
+		opt.maxArrayIncludeVector = includeVector
+	}
+}
+
+// WithBatchDimCheck makes ParseBatch report a single schema/data dimension mismatch error
+// when all the vectors of a field in the batch have the same unexpected dim.
+func WithBatchDimCheck() RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.batchDimCheck = true
+	}
+}
+
+// WithBatchPKTypeCheck makes ParseBatch report a single schema/data primary key type mismatch
+// error when the primary keys of all the rows in the batch have the same unexpected type.
+func WithBatchPKTypeCheck() RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.batchPKTypeCheck = true
+	}
+}
+
+// WithMaxCollectedErrors keeps at most n errors in the failures of ParseBatchWithResult to
+// bound the memory on a malformed batch, the other errors are only counted.
+func WithMaxCollectedErrors(n int) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.maxCollectedErrors = n
+	}
+}
+
+// WithRequireUniformSchema makes ParseBatch require every row to provide the same field keys
+// as the first row of the batch. The keys stored in the dynamic field are not compared.
+func WithRequireUniformSchema() RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.requireUniformSchema = true
+	}
+}
+
+// WithEmptyDynamicFieldCheck checks whether any row of a batch has a value for the dynamic field,
+// if none of them has, ParseBatch fails, or calls warn instead if it's not nil.
+func WithEmptyDynamicFieldCheck(warn func(fieldName string)) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.emptyDynamicCheck = true
+		opt.warnEmptyDynamic = warn
+	}
+}
+
+// WithLogger sets the logger which receives debug events when the parser
+// coerces, skips or defaults a value. Nothing is logged if no logger is set.
+func WithLogger(logger *zap.Logger) RowParserOption {
+	return func(opt *rowParserOption) {
+		opt.logger = logger
+	}
+}
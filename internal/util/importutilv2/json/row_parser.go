@@ -17,46 +17,118 @@
 package json
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"hash"
+	"math"
+	"math/big"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/cockroachdb/errors"
 	"github.com/samber/lo"
+	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
 	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/parameterutil"
 	"github.com/milvus-io/milvus/pkg/util/typeutil"
 )
 
 type RowParser interface {
 	Parse(raw any) (Row, error)
+	// PartitionKeyOf returns the partition key value of a parsed row,
+	// it can be used to route the row to the right partition.
+	PartitionKeyOf(row Row) (any, error)
+	// FieldType returns the data type of the field which can be provided in a row.
+	FieldType(name string) (schemapb.DataType, bool)
+	// FieldID returns the id of the field by its name, including the fields which are
+	// not provided by rows, such as the computed fields and the dynamic field.
+	FieldID(name string) (int64, bool)
+	// FieldElementType returns the element type of the array field which can be provided in a row.
+	FieldElementType(name string) (schemapb.DataType, bool)
+	// Dim returns the declared dim of the vector field.
+	Dim(fieldName string) (int, bool)
+	// HasDynamicField returns the name of the dynamic field, if the schema has one,
+	// the redundant keys of a row are stored in it.
+	HasDynamicField() (string, bool)
+	// ParseRaw parses a row whose values are not decoded yet, the values of
+	// the ignored and the unprojected keys are never decoded.
+	ParseRaw(raw map[string]json.RawMessage) (Row, error)
+	// FieldConverter returns the function which converts a single value of the field
+	// the same way as Parse, it can be applied to the values of a column.
+	FieldConverter(fieldID int64) (func(any) (any, error), error)
+	// ParseWithDynamicKeys parses the row, and returns the sorted top-level keys stored in the dynamic field.
+	ParseWithDynamicKeys(raw any) (Row, []string, error)
+	// ParseColumnar parses the rows into the typed columns, see ParseToSink.
+	ParseColumnar(raws []any, columns *ColumnBuffers) error
+	// ParseToSink parses the rows and appends the values into the sink field by field,
+	// parsing stops at the first invalid row, whose appended values are discarded.
+	ParseToSink(raws []any, sink ColumnSink) error
+	ParseBatch(raws []any) ([]Row, error)
+	// ParseAll parses the decoded top-level value, an array is parsed as the rows of a batch,
+	// and an object is parsed as a single row.
+	ParseAll(raw any) ([]Row, error)
+	// CheckAutoID checks whether the sampled rows provide the primary key as the autoID
+	// setting of the schema expects, before the whole file is parsed.
+	CheckAutoID(samples []any) error
+	ParseBatchWithResult(raws []any) ([]Row, *BatchResult, error)
+	ParseToFieldData(raws []any) ([]*schemapb.FieldData, error)
 }
 
 type rowParser struct {
-	dim          int
-	id2Field     map[int64]*schemapb.FieldSchema
-	name2FieldID map[string]int64
-	pkField      *schemapb.FieldSchema
-	dynamicField *schemapb.FieldSchema
+	dims              map[int64]int
+	vectorFields      []int64
+	id2Field          map[int64]*schemapb.FieldSchema
+	name2FieldID      map[string]int64
+	pkField           *schemapb.FieldSchema
+	partitionKeyField *schemapb.FieldSchema
+	dynamicField      *schemapb.FieldSchema
+
+	option       *rowParserOption
+	hashFuncs    map[int64]func() hash.Hash
+	alias2Name   map[string]string
+	name2Aliases map[string][]string
+	snake2Name   map[string]string
+	unprojected  typeutil.Set[string]
+	fastFields   *pkVectorFields
+	capacities   map[int64]int
+	maxLengths   map[int64]int
+	allowed      map[int64]typeutil.Set[any]
+	keyPattern   *regexp.Regexp
 }
 
-func NewRowParser(schema *schemapb.CollectionSchema) (RowParser, error) {
+func NewRowParser(schema *schemapb.CollectionSchema, opts ...RowParserOption) (RowParser, error) {
 	id2Field := lo.KeyBy(schema.GetFields(), func(field *schemapb.FieldSchema) int64 {
 		return field.GetFieldID()
 	})
-	vecField, err := typeutil.GetVectorFieldSchema(schema)
-	if err != nil {
-		return nil, err
+	vecFields := typeutil.GetVectorFieldSchemas(schema)
+	if len(vecFields) == 0 {
+		return nil, merr.WrapErrImportFailed("vector field is not found")
 	}
-	dim, err := typeutil.GetDim(vecField)
-	if err != nil {
-		return nil, err
+	dims := make(map[int64]int, len(vecFields))
+	for _, vecField := range vecFields {
+		dim, err := typeutil.GetDim(vecField)
+		if err != nil {
+			return nil, err
+		}
+		dims[vecField.GetFieldID()] = int(dim)
 	}
 	pkField, err := typeutil.GetPrimaryFieldSchema(schema)
 	if err != nil {
 		return nil, err
 	}
+	for _, field := range schema.GetFields() {
+		if field.GetDataType() == schemapb.DataType_Array && field.GetElementType() == schemapb.DataType_JSON {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("Array of JSON is not supported, field '%s' has element type JSON, "+
+				"please use a JSON field to store the whole array instead", field.GetName()))
+		}
+	}
 
 	name2FieldID := lo.SliceToMap(schema.GetFields(),
 		func(field *schemapb.FieldSchema) (string, int64) {
@@ -67,29 +139,469 @@ func NewRowParser(schema *schemapb.CollectionSchema) (RowParser, error) {
 		delete(name2FieldID, pkField.GetName())
 	}
 
+	var partitionKeyField *schemapb.FieldSchema
+	if typeutil.HasPartitionKey(schema) {
+		partitionKeyField, err = typeutil.GetPartitionKeyFieldSchema(schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	dynamicField := typeutil.GetDynamicField(schema)
 	if dynamicField != nil {
 		delete(name2FieldID, dynamicField.GetName())
 	}
-	return &rowParser{
-		dim:          int(dim),
-		id2Field:     id2Field,
-		name2FieldID: name2FieldID,
-		pkField:      pkField,
-		dynamicField: dynamicField,
-	}, nil
+	option := defaultRowParserOption()
+	for _, opt := range opts {
+		opt(option)
+	}
+	r := &rowParser{
+		dims:              dims,
+		vectorFields:      lo.Map(vecFields, func(field *schemapb.FieldSchema, _ int) int64 { return field.GetFieldID() }),
+		id2Field:          id2Field,
+		name2FieldID:      name2FieldID,
+		pkField:           pkField,
+		partitionKeyField: partitionKeyField,
+		dynamicField:      dynamicField,
+		option:            option,
+	}
+	if err = r.initHashFuncs(); err != nil {
+		return nil, err
+	}
+	if err = r.checkVectorNorms(); err != nil {
+		return nil, err
+	}
+	if err = r.checkHalfFloatFields(); err != nil {
+		return nil, err
+	}
+	if err = r.initComputedFields(); err != nil {
+		return nil, err
+	}
+	if err = r.initAliases(); err != nil {
+		return nil, err
+	}
+	if err = r.initNameNormalization(); err != nil {
+		return nil, err
+	}
+	for str := range r.option.truthyStrings {
+		if r.option.falsyStrings.Contain(str) {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("the string '%s' cannot be both truthy and falsy", str))
+		}
+	}
+	if sep := r.option.decimalSeparator; sep != 0 && (unicode.IsDigit(sep) || strings.ContainsRune("+-eE", sep)) {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("invalid decimal separator '%c'", sep))
+	}
+	if err = r.checkVectorComponentKeys(); err != nil {
+		return nil, err
+	}
+	if err = r.checkVectorEncodings(); err != nil {
+		return nil, err
+	}
+	if err = r.checkVectorColumns(); err != nil {
+		return nil, err
+	}
+	if err = r.checkVectorContainer(); err != nil {
+		return nil, err
+	}
+	if err = r.checkBitfields(); err != nil {
+		return nil, err
+	}
+	if err = r.checkIndexConstraints(); err != nil {
+		return nil, err
+	}
+	if err = r.checkEqualDimFields(); err != nil {
+		return nil, err
+	}
+	if err = r.checkTimestampFields(); err != nil {
+		return nil, err
+	}
+	if err = r.checkScaleFields(); err != nil {
+		return nil, err
+	}
+	if err = r.checkDeleteMarker(); err != nil {
+		return nil, err
+	}
+	if err = r.checkSchemaVersionKey(); err != nil {
+		return nil, err
+	}
+	for fieldID := range r.option.jsonStringFields {
+		if r.id2Field[fieldID].GetDataType() != schemapb.DataType_VarChar {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("JSON string is only supported for VarChar field, field id: %d", fieldID))
+		}
+	}
+	for fieldID := range r.option.pipelines {
+		if _, ok := r.id2Field[fieldID]; !ok {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("field %d of the pipeline is not found", fieldID))
+		}
+	}
+	for fieldID := range r.option.percentFields {
+		dataType := r.id2Field[fieldID].GetDataType()
+		if dataType != schemapb.DataType_Float && dataType != schemapb.DataType_Double {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("percentage is only supported for Float and Double field, field id: %d", fieldID))
+		}
+	}
+	for fieldID := range r.option.fieldByteOrders {
+		if r.id2Field[fieldID].GetDataType() != schemapb.DataType_FloatVector {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("byte order is only supported for FloatVector field, field id: %d", fieldID))
+		}
+	}
+	if r.option.requireDynamicValue && r.dynamicField == nil {
+		return nil, merr.WrapErrImportFailed("dynamic value is required, but dynamic field is not enabled")
+	}
+	if pattern := r.option.dynamicKeyPattern; pattern != "" {
+		if r.dynamicField == nil {
+			return nil, merr.WrapErrImportFailed("dynamic key pattern is set, but dynamic field is not enabled")
+		}
+		if r.keyPattern, err = regexp.Compile(pattern); err != nil {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("invalid dynamic key pattern '%s', error: %v", pattern, err))
+		}
+	}
+	for fieldID := range r.option.jsonKeyAllowlists {
+		if r.id2Field[fieldID].GetDataType() != schemapb.DataType_JSON {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("key allowlist is only supported for JSON field, field id: %d", fieldID))
+		}
+	}
+	for fieldID, rng := range r.option.numericRanges {
+		dataType := r.id2Field[fieldID].GetDataType()
+		if !typeutil.IsIntegerType(dataType) && !typeutil.IsFloatingType(dataType) {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("range is only supported for numeric field, field id: %d", fieldID))
+		}
+		if rng.Min > rng.Max {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("invalid range [%v, %v] of field '%s'",
+				rng.Min, rng.Max, r.id2Field[fieldID].GetName()))
+		}
+	}
+	for fieldID, rng := range r.option.componentRanges {
+		if r.id2Field[fieldID].GetDataType() != schemapb.DataType_FloatVector {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("component range is only supported for FloatVector field, field id: %d", fieldID))
+		}
+		if rng.Min > rng.Max {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("invalid component range [%v, %v] of field '%s'",
+				rng.Min, rng.Max, r.id2Field[fieldID].GetName()))
+		}
+	}
+	for fieldID := range r.option.clampIntegerFields {
+		switch r.id2Field[fieldID].GetDataType() {
+		case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32:
+		default:
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("clamp is only supported for Int8, Int16 and Int32 field, field id: %d", fieldID))
+		}
+	}
+	for fieldID := range r.option.dedupArrayFields {
+		if r.id2Field[fieldID].GetDataType() != schemapb.DataType_Array {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("dedup is only supported for Array field, field id: %d", fieldID))
+		}
+	}
+	for _, fieldID := range append(r.option.trimArrayFields.Collect(), r.option.dropEmptyArrayFields.Collect()...) {
+		field := r.id2Field[fieldID]
+		if field.GetDataType() != schemapb.DataType_Array || field.GetElementType() != schemapb.DataType_VarChar {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("trim and drop empty are only supported for Array<VarChar> field, field id: %d", fieldID))
+		}
+	}
+	r.initLimits()
+	if err = r.initAllowedValues(); err != nil {
+		return nil, err
+	}
+	if err = r.initProjection(); err != nil {
+		return nil, err
+	}
+	if r.fastFields = newPKVectorFields(schema, len(opts)); r.fastFields != nil {
+		r.fastFields.dim = r.dims[r.fastFields.vecID]
+	}
+	return r, nil
+}
+
+// initLimits collects the max_capacity of the Array fields and the max_length of
+// the VarChar fields and the Array<VarChar> fields, which declare them.
+func (r *rowParser) initLimits() {
+	r.capacities = make(map[int64]int)
+	r.maxLengths = make(map[int64]int)
+	for fieldID, field := range r.id2Field {
+		if field.GetDataType() == schemapb.DataType_Array {
+			if capacity, err := parameterutil.GetMaxCapacity(field); err == nil {
+				r.capacities[fieldID] = int(capacity)
+			}
+		}
+		if typeutil.IsStringType(field.GetDataType()) || typeutil.IsStringType(field.GetElementType()) {
+			if maxLength, err := parameterutil.GetMaxLength(field); err == nil {
+				r.maxLengths[fieldID] = int(maxLength)
+			}
+		}
+	}
+}
+
+// initAllowedValues checks the allowed values against the types of the fields, the integers
+// are kept as int64 so that they're comparable with the parsed values of any integer type.
+func (r *rowParser) initAllowedValues() error {
+	r.allowed = make(map[int64]typeutil.Set[any])
+	for fieldID, values := range r.option.allowedValues {
+		field := r.id2Field[fieldID]
+		dataType := field.GetDataType()
+		if dataType != schemapb.DataType_VarChar && !typeutil.IsIntegerType(dataType) {
+			return merr.WrapErrImportFailed(fmt.Sprintf("allowed values are only supported for VarChar and integer field, field id: %d", fieldID))
+		}
+		set := typeutil.NewSet[any]()
+		for _, value := range values {
+			normalized, ok := normalizeAllowedValue(value)
+			if _, isString := normalized.(string); !ok || isString != (dataType == schemapb.DataType_VarChar) {
+				return merr.WrapErrImportFailed(fmt.Sprintf("invalid allowed value '%v' of %s field '%s'",
+					value, dataType.String(), field.GetName()))
+			}
+			set.Insert(normalized)
+		}
+		r.allowed[fieldID] = set
+	}
+	return nil
+}
+
+// normalizeAllowedValue converts the integers to int64, it returns false for the values which are
+// neither strings nor integers.
+func normalizeAllowedValue(value any) (any, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+// initProjection removes the fields which are not projected, their keys are ignored.
+func (r *rowParser) initProjection() error {
+	r.unprojected = typeutil.NewSet[string]()
+	if len(r.option.projectFields) == 0 {
+		return nil
+	}
+	projected := typeutil.NewSet[int64]()
+	for _, fieldID := range r.option.projectFields {
+		field, ok := r.id2Field[fieldID]
+		if !ok || field.GetIsDynamic() {
+			return merr.WrapErrImportFailed(fmt.Sprintf("projected field %d is not found in schema", fieldID))
+		}
+		projected.Insert(fieldID)
+	}
+	for name, fieldID := range r.name2FieldID {
+		if !projected.Contain(fieldID) {
+			r.unprojected.Insert(name)
+			delete(r.name2FieldID, name)
+			delete(r.dims, fieldID)
+		}
+	}
+	if r.partitionKeyField != nil && !projected.Contain(r.partitionKeyField.GetFieldID()) {
+		r.partitionKeyField = nil
+	}
+	return nil
+}
+
+func (r *rowParser) initAliases() error {
+	r.alias2Name = make(map[string]string)
+	r.name2Aliases = make(map[string][]string)
+	for name, aliases := range r.option.aliases {
+		if _, ok := r.name2FieldID[name]; !ok {
+			return merr.WrapErrImportFailed(fmt.Sprintf("the aliased field '%s' is not defined in schema", name))
+		}
+		for _, alias := range aliases {
+			if alias == name {
+				continue
+			}
+			if _, ok := r.name2FieldID[alias]; ok {
+				return merr.WrapErrImportFailed(fmt.Sprintf("alias '%s' of field '%s' collides with another field name", alias, name))
+			}
+			if other, ok := r.alias2Name[alias]; ok {
+				if other != name {
+					return merr.WrapErrImportFailed(fmt.Sprintf("alias '%s' is used by both field '%s' and field '%s'", alias, other, name))
+				}
+				continue
+			}
+			r.alias2Name[alias] = name
+			r.name2Aliases[name] = append(r.name2Aliases[name], alias)
+		}
+	}
+	return nil
+}
+
+func (r *rowParser) initHashFuncs() error {
+	r.hashFuncs = make(map[int64]func() hash.Hash, len(r.option.varCharHashes))
+	for fieldID, algorithm := range r.option.varCharHashes {
+		field, ok := r.id2Field[fieldID]
+		if !ok || !typeutil.IsStringType(field.GetDataType()) {
+			return merr.WrapErrImportFailed(fmt.Sprintf("hash is only supported for VarChar field, field id: %d", fieldID))
+		}
+		newHash, err := newHashFunc(algorithm)
+		if err != nil {
+			return err
+		}
+		r.hashFuncs[fieldID] = newHash
+	}
+	return nil
+}
+
+func (r *rowParser) checkVectorComponentKeys() error {
+	for fieldID, keys := range r.option.vectorComponentKeys {
+		field, ok := r.id2Field[fieldID]
+		if !ok || field.GetDataType() != schemapb.DataType_FloatVector {
+			return merr.WrapErrImportFailed(fmt.Sprintf("component keys are only supported for FloatVector field, field id: %d", fieldID))
+		}
+		if len(keys) != r.dims[fieldID] || len(lo.Uniq(keys)) != len(keys) {
+			return merr.WrapErrImportFailed(fmt.Sprintf("field '%s' expects %d distinct component keys, got %v",
+				field.GetName(), r.dims[fieldID], keys))
+		}
+	}
+	for fieldID := range r.option.indexKeyedVectors {
+		if r.id2Field[fieldID].GetDataType() != schemapb.DataType_FloatVector {
+			return merr.WrapErrImportFailed(fmt.Sprintf("index-keyed vector is only supported for FloatVector field, field id: %d", fieldID))
+		}
+		if _, ok := r.option.vectorComponentKeys[fieldID]; ok {
+			return merr.WrapErrImportFailed(fmt.Sprintf("field '%s' cannot have both component keys and index keys",
+				r.id2Field[fieldID].GetName()))
+		}
+	}
+	return nil
+}
+
+func (r *rowParser) checkEqualDimFields() error {
+	for _, fieldID := range r.option.equalDimFields {
+		dim, ok := r.dims[fieldID]
+		if !ok {
+			return merr.WrapErrImportFailed(fmt.Sprintf("equal dim is only supported for vector field, field id: %d", fieldID))
+		}
+		first := r.option.equalDimFields[0]
+		if dim != r.dims[first] {
+			return merr.WrapErrImportFailed(fmt.Sprintf("the vector fields '%s' and '%s' are required to have equal dim, but the schema declares %d and %d",
+				r.id2Field[first].GetName(), r.id2Field[fieldID].GetName(), r.dims[first], dim))
+		}
+	}
+	return nil
+}
+
+// checkRowEqualDim reports the vectors of the row with different dims, before they are
+// parsed and reported one by one against the schema.
+func (r *rowParser) checkRowEqualDim(stringMap map[string]any) error {
+	dims := make([]string, 0, len(r.option.equalDimFields))
+	observed := -1
+	misaligned := false
+	for _, fieldID := range r.option.equalDimFields {
+		field := r.id2Field[fieldID]
+		value, _ := r.lookupValue(stringMap, field.GetName())
+		d, ok := r.observedDim(fieldID, value)
+		if !ok {
+			continue
+		}
+		if observed != -1 && d != observed {
+			misaligned = true
+		}
+		observed = d
+		dims = append(dims, fmt.Sprintf("%s=%d", field.GetName(), d))
+	}
+	if misaligned {
+		return newParseError(ErrKindDimMismatch, nil, nil, merr.WrapErrImportFailed(
+			fmt.Sprintf("the vectors of the row are required to have equal dim, got %s", strings.Join(dims, ", "))))
+	}
+	return nil
+}
+
+func (r *rowParser) checkIndexConstraints() error {
+	for fieldID, constraint := range r.option.indexConstraints {
+		dim, ok := r.dims[fieldID]
+		if !ok {
+			return merr.WrapErrImportFailed(fmt.Sprintf("index constraint is only supported for vector field, field id: %d", fieldID))
+		}
+		if (constraint.MinDim > 0 && dim < constraint.MinDim) || (constraint.MaxDim > 0 && dim > constraint.MaxDim) {
+			return merr.WrapErrImportFailed(fmt.Sprintf("dim %d of field '%s' is not supported by index '%s', "+
+				"which requires dim in [%d, %d]", dim, r.id2Field[fieldID].GetName(), constraint.IndexType,
+				constraint.MinDim, constraint.MaxDim))
+		}
+	}
+	return nil
+}
+
+func (r *rowParser) initComputedFields() error {
+	for _, computed := range r.option.computedFields {
+		field, ok := r.id2Field[computed.fieldID]
+		if !ok {
+			return merr.WrapErrImportFailed(fmt.Sprintf("computed field %d is not found in schema", computed.fieldID))
+		}
+		if field.GetIsDynamic() || (field.GetIsPrimaryKey() && field.GetAutoID()) {
+			return merr.WrapErrImportFailed(fmt.Sprintf("field '%s' cannot be computed", field.GetName()))
+		}
+		// computed fields are not required from the input
+		delete(r.name2FieldID, field.GetName())
+	}
+	return nil
+}
+
+func (r *rowParser) isComputedField(fieldID int64) bool {
+	for _, computed := range r.option.computedFields {
+		if computed.fieldID == fieldID {
+			return true
+		}
+	}
+	return false
+}
+
+// fillComputedFields computes the fields in the order of registration,
+// a computed field can use the value of the fields computed before it.
+func (r *rowParser) fillComputedFields(row Row) error {
+	for _, computed := range r.option.computedFields {
+		field := r.id2Field[computed.fieldID]
+		value, err := computed.fn(row)
+		if err != nil {
+			return merr.WrapErrImportFailed(fmt.Sprintf("failed to compute field '%s', error: %v", field.GetName(), err))
+		}
+		data, err := r.parseEntity(computed.fieldID, value)
+		if err != nil {
+			return err
+		}
+		row[computed.fieldID] = data
+	}
+	return nil
 }
 
 func (r *rowParser) wrapTypeError(v any, fieldID int64) error {
 	field := r.id2Field[fieldID]
-	return merr.WrapErrImportFailed(fmt.Sprintf("expected type '%s' for field '%s', got type '%T' with value '%v'",
-		field.GetDataType().String(), field.GetName(), v, v))
+	return newParseError(ErrKindTypeMismatch, field, v, merr.WrapErrImportFailed(fmt.Sprintf("expected type '%s' for field '%s', got type '%T' with value '%v'",
+		field.GetDataType().String(), field.GetName(), v, v)))
 }
 
 func (r *rowParser) wrapDimError(actualDim int, fieldID int64) error {
 	field := r.id2Field[fieldID]
-	return merr.WrapErrImportFailed(fmt.Sprintf("expected dim '%d' for field '%s' with type '%s', got dim '%d'",
-		r.dim, field.GetName(), field.GetDataType().String(), actualDim))
+	return newParseError(ErrKindDimMismatch, field, actualDim, merr.WrapErrImportFailed(fmt.Sprintf("expected dim '%d' for field '%s' with type '%s', got dim '%d'",
+		r.dims[fieldID], field.GetName(), field.GetDataType().String(), actualDim)))
+}
+
+func (r *rowParser) wrapEmptyVectorError(fieldID int64) error {
+	field := r.id2Field[fieldID]
+	return newParseError(ErrKindEmptyVector, field, nil, merr.WrapErrImportFailed(fmt.Sprintf("empty vector for field '%s' with type '%s', expected dim '%d', "+
+		"the field may be missing or null in the source data", field.GetName(), field.GetDataType().String(), r.dims[fieldID])))
+}
+
+func (r *rowParser) wrapByteValueError(value json.Number, index int, fieldID int64) error {
+	field := r.id2Field[fieldID]
+	if _, err := strconv.ParseInt(value.String(), 0, 64); err == nil && strings.HasPrefix(value.String(), "-") {
+		return newParseError(ErrKindInvalidValue, field, value, merr.WrapErrImportFailed(fmt.Sprintf(
+			"field '%s' byte at index %d must be 0-255; got negative value %s, "+
+				"signed bytes should be converted to unsigned, e.g. -1 to 255", field.GetName(), index, value)))
+	}
+	if field.GetDataType() == schemapb.DataType_FloatVector {
+		return newParseError(ErrKindInvalidValue, field, value, merr.WrapErrImportFailed(fmt.Sprintf("field '%s' expects byte values in [0, 255] for float32 bytes, "+
+			"element at index %d is '%s'", field.GetName(), index, value)))
+	}
+	if strings.ContainsAny(value.String(), ".eE") {
+		return newParseError(ErrKindInvalidValue, field, value, merr.WrapErrImportFailed(fmt.Sprintf("field '%s' expects packed byte values for a %s, "+
+			"element at index %d looks like a float '%s', did you mean a FloatVector?",
+			field.GetName(), field.GetDataType().String(), index, value)))
+	}
+	return newParseError(ErrKindInvalidValue, field, value, merr.WrapErrImportFailed(fmt.Sprintf("field '%s' expects packed byte values in [0, 255] for a %s, "+
+		"element at index %d is '%s'", field.GetName(), field.GetDataType().String(), index, value)))
 }
 
 func (r *rowParser) wrapArrayValueTypeError(v any, eleType schemapb.DataType) error {
@@ -98,124 +610,703 @@ func (r *rowParser) wrapArrayValueTypeError(v any, eleType schemapb.DataType) er
 }
 
 func (r *rowParser) Parse(raw any) (Row, error) {
+	if r.fastFields != nil {
+		if stringMap, ok := raw.(map[string]any); ok {
+			if row, ok := r.parseFast(stringMap); ok {
+				return row, nil
+			}
+		}
+	}
+	return r.parse(raw, nil)
+}
+
+func (r *rowParser) ParseWithDynamicKeys(raw any) (Row, []string, error) {
+	dynamicKeys := make([]string, 0)
+	row, err := r.parse(raw, &dynamicKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+	return row, dynamicKeys, nil
+}
+
+// parse parses the row, and collects the sorted keys stored in the dynamic field
+// into dynamicKeys if it's not nil.
+func (r *rowParser) parse(raw any, dynamicKeys *[]string) (Row, error) {
+	row := make(Row)
+	if err := r.parseInto(raw, dynamicKeys, rowSink(row)); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// parseInto parses the row, and appends the values into the sink field by field.
+// A key matching a field, by its name or an alias, always binds to the field even if
+// the dynamic field is enabled, a field provided by more than one such key is rejected,
+// and the other keys are stored in the dynamic field. The computed fields and the row
+// size limit need the whole row, they are applied only if the sink is a Row.
+func (r *rowParser) parseInto(raw any, dynamicKeys *[]string, sink ColumnSink) error {
 	stringMap, ok := raw.(map[string]any)
 	if !ok {
-		return nil, merr.WrapErrImportFailed("invalid JSON format, each row should be a key-value map")
+		return merr.WrapErrImportFailed("invalid JSON format, each row should be a key-value map")
 	}
-	if _, ok = stringMap[r.pkField.GetName()]; ok && r.pkField.GetAutoID() {
-		return nil, merr.WrapErrImportFailed(
+	if r.option.maxFieldsPerRow > 0 && len(stringMap) > r.option.maxFieldsPerRow {
+		return r.wrapFieldCountError(len(stringMap), stringMap[r.pkField.GetName()])
+	}
+	if r.option.schemaVersionKey != "" {
+		if err := r.checkRowSchemaVersion(stringMap); err != nil {
+			return err
+		}
+	}
+	if r.option.deleteMarker != "" {
+		row, deleted, err := r.parseDeleteMarker(stringMap)
+		if err != nil {
+			return err
+		}
+		if deleted {
+			for fieldID, value := range row {
+				if err = sink.Append(fieldID, value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	if len(r.option.vectorColumns) > 0 {
+		var err error
+		if stringMap, err = r.assembleVectorColumns(stringMap); err != nil {
+			return err
+		}
+	}
+	if r.option.vectorContainer != "" {
+		var err error
+		if stringMap, err = r.expandVectorContainer(stringMap); err != nil {
+			return err
+		}
+	}
+	if r.pkField.GetAutoID() && r.hasPK(stringMap) {
+		return merr.WrapErrImportFailed(
 			fmt.Sprintf("the primary key '%s' is auto-generated, no need to provide", r.pkField.GetName()))
 	}
-	dynamicValues := make(map[string]any)
-	row := make(Row)
-	for key, value := range stringMap {
-		if fieldID, ok := r.name2FieldID[key]; ok {
-			data, err := r.parseEntity(fieldID, value)
+	if r.partitionKeyField != nil && !r.isComputedField(r.partitionKeyField.GetFieldID()) {
+		if value, ok := r.lookupValue(stringMap, r.partitionKeyField.GetName()); !ok || value == nil {
+			return merr.WrapErrImportFailed(
+				fmt.Sprintf("value of partition key field '%s' is missed, partition key is required and cannot be null",
+					r.partitionKeyField.GetName()))
+		}
+	}
+	for _, computed := range r.option.computedFields {
+		name := r.id2Field[computed.fieldID].GetName()
+		if _, ok := stringMap[name]; ok {
+			return merr.WrapErrImportFailed(
+				fmt.Sprintf("the field '%s' is computed, no need to provide", name))
+		}
+	}
+	if len(r.option.equalDimFields) > 1 {
+		if err := r.checkRowEqualDim(stringMap); err != nil {
+			return err
+		}
+	}
+	dynamicValues := make(map[string]any)
+	var existingDynamic, pk any
+	provided := 0
+	for key, value := range stringMap {
+		if r.option.ignoreKeys.Contain(key) || r.isControlKey(key) {
+			continue
+		}
+		name, aliased := r.aliasOf(key)
+		if aliased {
+			key = name
+		}
+		if r.unprojected.Contain(key) {
+			continue
+		}
+		if fieldID, ok := r.name2FieldID[key]; ok {
+			// the field and its aliases are all provided
+			if aliased && r.countProvided(stringMap, key) > 1 {
+				return merr.WrapErrImportFailed(fmt.Sprintf("the field '%s' is provided more than once by aliases", key))
+			}
+			data, err := r.parseField(fieldID, value)
+			if err != nil {
+				return err
+			}
+			if fieldID == r.pkField.GetFieldID() {
+				pk = data
+			}
+			if err = sink.Append(fieldID, data); err != nil {
+				return err
+			}
+			provided++
+		} else if r.dynamicField != nil {
+			if key == r.dynamicField.GetName() {
+				if !r.option.dynamicMerge {
+					return merr.WrapErrImportFailed(
+						fmt.Sprintf("dynamic field is enabled, explicit specification of '%s' is not allowed", key))
+				}
+				existingDynamic = value
+				continue
+			}
+			if r.keyPattern != nil && !r.keyPattern.MatchString(key) {
+				err := newParseError(ErrKindUnknownField, nil, nil, merr.WrapErrImportFailed(
+					fmt.Sprintf("the field '%s' is not defined in schema, nor matches the dynamic key pattern '%s'", key, r.keyPattern.String())))
+				err.FieldName = key
+				return err
+			}
+			// has dynamic field, put redundant pair to dynamicValues
+			r.warnSimilarKey(key)
+			dynamicValues[key] = value
+		} else if key == common.MetaFieldName {
+			err := newParseError(ErrKindUnknownField, nil, nil, merr.WrapErrImportFailed(
+				fmt.Sprintf("the key '%s' is reserved for the dynamic field, but dynamic field is not enabled "+
+					"on this collection, please remove it or enable dynamic field", key)))
+			err.FieldName = key
+			return err
+		} else {
+			err := newParseError(ErrKindUnknownField, nil, nil,
+				merr.WrapErrImportFailed(fmt.Sprintf("the field '%s' is not defined in schema", key)))
+			err.FieldName = key
+			return err
+		}
+	}
+	if provided < len(r.name2FieldID) {
+		for fieldName, fieldID := range r.name2FieldID {
+			if r.countProvided(stringMap, fieldName) == 0 {
+				return newParseError(ErrKindMissingField, r.id2Field[fieldID], nil,
+					merr.WrapErrImportFailed(fmt.Sprintf("value of field '%s' is missed", fieldName)))
+			}
+		}
+	}
+	row, isRow := sink.(rowSink)
+	if isRow {
+		if err := r.fillComputedFields(Row(row)); err != nil {
+			return err
+		}
+	}
+	if r.dynamicField != nil {
+		if existingDynamic != nil {
+			var err error
+			dynamicValues, err = r.mergeDynamicValues(existingDynamic, dynamicValues)
+			if err != nil {
+				return err
+			}
+		}
+		if dynamicKeys != nil {
+			*dynamicKeys = lo.Keys(dynamicValues)
+			sort.Strings(*dynamicKeys)
+		}
+		// combine the redundant pairs into dynamic field(if it has)
+		if err := r.combineDynamicRow(dynamicValues, sink, pk); err != nil {
+			return err
+		}
+	}
+	if isRow {
+		return r.checkRowSize(Row(row))
+	}
+	return nil
+}
+
+// countProvided returns the number of keys in the row which provide the field, by its name or aliases.
+func (r *rowParser) countProvided(stringMap map[string]any, name string) int {
+	count := 0
+	if r.hasKey(stringMap, name) {
+		count++
+	}
+	if r.snake2Name != nil {
+		// the normalized forms are not enumerable, so each key of the row is resolved
+		for key := range stringMap {
+			if n, ok := r.aliasOf(key); ok && n == name && r.hasKey(stringMap, key) {
+				count++
+			}
+		}
+		return count
+	}
+	for _, alias := range r.name2Aliases[name] {
+		if r.hasKey(stringMap, alias) {
+			count++
+		}
+	}
+	return count
+}
+
+// hasKey returns whether the row has the key, which is neither ignored nor a control key.
+func (r *rowParser) hasKey(stringMap map[string]any, key string) bool {
+	_, ok := stringMap[key]
+	return ok && !r.option.ignoreKeys.Contain(key) && !r.isControlKey(key)
+}
+
+func (r *rowParser) checkRowSize(row Row) error {
+	if r.option.maxRowBytes <= 0 {
+		return nil
+	}
+	size := EstimateRowSize(row)
+	if size <= r.option.maxRowBytes {
+		return nil
+	}
+	if pk, ok := row[r.pkField.GetFieldID()]; ok {
+		return merr.WrapErrImportFailed(fmt.Sprintf("the estimated size %d bytes of row with primary key '%v' exceeds the limit %d bytes",
+			size, pk, r.option.maxRowBytes))
+	}
+	return merr.WrapErrImportFailed(fmt.Sprintf("the estimated size %d bytes of row exceeds the limit %d bytes",
+		size, r.option.maxRowBytes))
+}
+
+// wrapFieldCountError reports the row with too many keys, by its primary key if it's provided.
+func (r *rowParser) wrapFieldCountError(count int, pk any) error {
+	if pk != nil {
+		return merr.WrapErrImportFailed(fmt.Sprintf("the row with primary key '%v' has %d keys, exceeds the limit %d keys per row",
+			pk, count, r.option.maxFieldsPerRow))
+	}
+	return merr.WrapErrImportFailed(fmt.Sprintf("the row has %d keys, exceeds the limit %d keys per row",
+		count, r.option.maxFieldsPerRow))
+}
+
+func (r *rowParser) ParseRaw(raw map[string]json.RawMessage) (Row, error) {
+	if r.option.maxFieldsPerRow > 0 && len(raw) > r.option.maxFieldsPerRow {
+		var pk any
+		if value, ok := raw[r.pkField.GetName()]; ok {
+			pk = string(value)
+		}
+		return nil, r.wrapFieldCountError(len(raw), pk)
+	}
+	stringMap := make(map[string]any, len(raw))
+	for key, value := range raw {
+		name := key
+		if n, ok := r.aliasOf(key); ok {
+			name = n
+		}
+		if r.option.ignoreKeys.Contain(key) || r.unprojected.Contain(name) {
+			continue
+		}
+		decoded, err := decodeValue(value)
+		if err != nil {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("failed to decode the value of key '%s', error: %v", key, err))
+		}
+		stringMap[key] = decoded
+	}
+	return r.Parse(stringMap)
+}
+
+func (r *rowParser) FieldConverter(fieldID int64) (func(any) (any, error), error) {
+	field, ok := r.id2Field[fieldID]
+	if !ok || r.name2FieldID[field.GetName()] != fieldID {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("field %d is not found or not provided by rows", fieldID))
+	}
+	return func(value any) (any, error) {
+		return r.parseField(fieldID, value)
+	}, nil
+}
+
+// parseField parses the value of the field and validates it.
+func (r *rowParser) parseField(fieldID int64, value any) (any, error) {
+	value, err := r.runPipeline(fieldID, value)
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.parseEntity(fieldID, value)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.checkNumericRange(data, fieldID); err != nil {
+		return nil, err
+	}
+	if err = r.checkComponentRange(data, fieldID); err != nil {
+		return nil, err
+	}
+	if err = r.checkAllowedValue(data, fieldID); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// runPipeline runs the stages of the field's pipeline in order, it stops at the first error.
+func (r *rowParser) runPipeline(fieldID int64, value any) (any, error) {
+	for i, fn := range r.option.pipelines[fieldID] {
+		res, err := fn(value)
+		if err != nil {
+			field := r.id2Field[fieldID]
+			return nil, newParseError(ErrKindInvalidValue, field, value, merr.WrapErrImportFailed(
+				fmt.Sprintf("stage %d of the pipeline of field '%s' failed, error: %v", i, field.GetName(), err)))
+		}
+		value = res
+	}
+	return value, nil
+}
+
+// isControlKey returns whether the key is the delete marker or the schema version key,
+// which are neither fields nor stored in the dynamic field.
+func (r *rowParser) isControlKey(key string) bool {
+	return key != "" && (key == r.option.deleteMarker || key == r.option.schemaVersionKey)
+}
+
+// lookupValue returns the value of the field from the row, by its name or one of its aliases.
+func (r *rowParser) lookupValue(stringMap map[string]any, name string) (any, bool) {
+	if value, ok := stringMap[name]; ok {
+		return value, true
+	}
+	if r.snake2Name != nil {
+		for key, value := range stringMap {
+			if n, ok := r.aliasOf(key); ok && n == name {
+				return value, true
+			}
+		}
+		return nil, false
+	}
+	for _, alias := range r.name2Aliases[name] {
+		if value, ok := stringMap[alias]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func (r *rowParser) PartitionKeyOf(row Row) (any, error) {
+	if r.partitionKeyField == nil {
+		return nil, merr.WrapErrImportFailed("the collection has no partition key field")
+	}
+	value, ok := row[r.partitionKeyField.GetFieldID()]
+	if !ok {
+		return nil, merr.WrapErrImportFailed(
+			fmt.Sprintf("value of partition key field '%s' is missed", r.partitionKeyField.GetName()))
+	}
+	return value, nil
+}
+
+func (r *rowParser) FieldType(name string) (schemapb.DataType, bool) {
+	fieldID, ok := r.name2FieldID[name]
+	if !ok {
+		return schemapb.DataType_None, false
+	}
+	return r.id2Field[fieldID].GetDataType(), true
+}
+
+func (r *rowParser) FieldID(name string) (int64, bool) {
+	for fieldID, field := range r.id2Field {
+		if field.GetName() == name {
+			return fieldID, true
+		}
+	}
+	return 0, false
+}
+
+func (r *rowParser) FieldElementType(name string) (schemapb.DataType, bool) {
+	fieldID, ok := r.name2FieldID[name]
+	if !ok || r.id2Field[fieldID].GetDataType() != schemapb.DataType_Array {
+		return schemapb.DataType_None, false
+	}
+	return r.id2Field[fieldID].GetElementType(), true
+}
+
+func (r *rowParser) Dim(fieldName string) (int, bool) {
+	fieldID, ok := r.name2FieldID[fieldName]
+	if !ok {
+		return 0, false
+	}
+	dim, ok := r.dims[fieldID]
+	return dim, ok
+}
+
+func (r *rowParser) HasDynamicField() (string, bool) {
+	if r.dynamicField == nil {
+		return "", false
+	}
+	return r.dynamicField.GetName(), true
+}
+
+// mergeDynamicValues deep merges the redundant pairs into the existing dynamic field value,
+// conflicts are resolved by the configured merge policy.
+func (r *rowParser) mergeDynamicValues(existing any, dynamicValues map[string]any) (map[string]any, error) {
+	var base map[string]any
+	switch v := existing.(type) {
+	case string:
+		dec := json.NewDecoder(strings.NewReader(v))
+		dec.UseNumber()
+		if err := dec.Decode(&base); err != nil {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("failed to decode the value of dynamic field '%s', error: %v",
+				r.dynamicField.GetName(), err))
+		}
+	case map[string]any:
+		base = v
+	default:
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("the value of dynamic field '%s' should be a JSON object, got '%v'",
+			r.dynamicField.GetName(), existing))
+	}
+	// a key matching a field always binds to the field, so it's ambiguous in the dynamic field
+	for key := range base {
+		name := key
+		if n, ok := r.aliasOf(key); ok {
+			name = n
+		}
+		if _, ok := r.name2FieldID[name]; ok {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("the key '%s' of dynamic field '%s' collides with the field '%s'",
+				key, r.dynamicField.GetName(), name))
+		}
+	}
+	return r.deepMerge(base, dynamicValues, "")
+}
+
+func (r *rowParser) deepMerge(dst, src map[string]any, path string) (map[string]any, error) {
+	res := make(map[string]any, len(dst)+len(src))
+	for key, value := range dst {
+		res[key] = value
+	}
+	for key, value := range src {
+		old, ok := res[key]
+		if !ok {
+			res[key] = value
+			continue
+		}
+		oldMap, ok1 := old.(map[string]any)
+		newMap, ok2 := value.(map[string]any)
+		if ok1 && ok2 {
+			merged, err := r.deepMerge(oldMap, newMap, path+key+".")
+			if err != nil {
+				return nil, err
+			}
+			res[key] = merged
+			continue
+		}
+		if r.option.dynamicMergePolicy == DynamicMergeError {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("conflict dynamic key '%s%s' between dynamic field '%s' and the row",
+				path, key, r.dynamicField.GetName()))
+		}
+		res[key] = value
+	}
+	return res, nil
+}
+
+func (r *rowParser) combineDynamicRow(dynamicValues map[string]any, sink ColumnSink, pk any) error {
+	// Combine the dynamic field value
+	// invalid inputs:
+	// case 1: {"id": 1, "vector": [], "$meta": {"x": 8}} ==>> "$meta" is not allowed
+	// valid inputs:
+	// case 2: {"id": 1, "vector": [], "x": 8} ==>> {"id": 1, "vector": [], "$meta": "{\"x\": 8}"}
+	// case 3: {"id": 1, "vector": []}
+	dynamicFieldID := r.dynamicField.GetFieldID()
+	if len(dynamicValues) > 0 {
+		// case 2
+		normalized, err := normalizeJSONValue(dynamicValues, "$")
+		if err != nil {
+			return merr.WrapErrImportFailed(fmt.Sprintf("invalid value of dynamic field '%s', error: %v", r.dynamicField.GetName(), err))
+		}
+		dynamicValues = normalized.(map[string]any)
+		if r.option.canonicalDynamicField {
+			dynamicValues = canonicalizeJSONValue(dynamicValues).(map[string]any)
+		}
+		if len(r.option.dynamicKeyOrder) > 0 {
+			data, err := marshalOrdered(dynamicValues, r.option.dynamicKeyOrder)
+			if err != nil {
+				return err
+			}
+			return sink.Append(dynamicFieldID, data)
+		}
+		data, err := r.parseEntity(dynamicFieldID, dynamicValues)
+		if err != nil {
+			return err
+		}
+		return sink.Append(dynamicFieldID, data)
+	}
+	// case 3
+	if r.option.requireDynamicValue {
+		return r.wrapEmptyDynamicError(pk)
+	}
+	if logger := r.option.logger; logger != nil {
+		logger.Debug("no dynamic value in row, use default value for dynamic field",
+			zap.String("field", r.dynamicField.GetName()))
+	}
+	return sink.Append(dynamicFieldID, []byte("{}"))
+}
+
+// wrapEmptyDynamicError reports the row which stores nothing in the required dynamic field,
+// by its primary key if it's provided.
+func (r *rowParser) wrapEmptyDynamicError(pk any) error {
+	name := r.dynamicField.GetName()
+	msg := fmt.Sprintf("the row has no value for dynamic field '%s', at least one key not defined in schema is required", name)
+	if pk != nil {
+		msg = fmt.Sprintf("the row with primary key '%v' has no value for dynamic field '%s', "+
+			"at least one key not defined in schema is required", pk, name)
+	}
+	return newParseError(ErrKindMissingField, r.dynamicField, nil, merr.WrapErrImportFailed(msg))
+}
+
+// floatNumber returns the number of a Float or Double field, a string is accepted
+// if the decimal separator is configured.
+func (r *rowParser) floatNumber(obj any, fieldID int64) (json.Number, error) {
+	if v, ok := obj.(string); ok && strings.HasSuffix(v, "%") {
+		if divide, ok := r.option.percentFields[fieldID]; ok {
+			return r.parsePercentage(v, fieldID, divide)
+		}
+	}
+	if v, ok := obj.(string); ok && r.option.decimalSeparator != 0 {
+		sep := r.option.decimalSeparator
+		if strings.Count(v, string(sep)) > 1 || (sep != '.' && strings.ContainsRune(v, '.')) {
+			return "", newParseError(ErrKindInvalidValue, r.id2Field[fieldID], v, merr.WrapErrImportFailed(
+				fmt.Sprintf("ambiguous number '%s' for field '%s' with decimal separator '%c'", v, r.id2Field[fieldID].GetName(), sep)))
+		}
+		str := strings.Replace(v, string(sep), ".", 1)
+		// json.Valid rejects the forms which are not JSON numbers, such as "NaN" and "0x1p-2"
+		if _, err := strconv.ParseFloat(str, 64); err != nil || !json.Valid([]byte(str)) {
+			return "", newParseError(ErrKindInvalidValue, r.id2Field[fieldID], v, merr.WrapErrImportFailed(
+				fmt.Sprintf("invalid number '%s' for field '%s'", v, r.id2Field[fieldID].GetName())))
+		}
+		return json.Number(str), nil
+	}
+	if value, ok := r.asNumber(obj); ok {
+		return value, nil
+	}
+	return "", r.wrapTypeError(obj, fieldID)
+}
+
+// checkIntegerForFloat rejects or counts the integer value of a Float or Double field.
+func (r *rowParser) checkIntegerForFloat(value json.Number, fieldID int64) error {
+	if strings.ContainsAny(value.String(), ".eE") {
+		return nil
+	}
+	if !r.option.acceptIntegerForFloat {
+		field := r.id2Field[fieldID]
+		return merr.WrapErrImportFailed(fmt.Sprintf("field '%s' of type '%s' expects a float-formatted value, got integer '%s'",
+			field.GetName(), field.GetDataType().String(), value))
+	}
+	if r.option.integerForFloatCount != nil {
+		r.option.integerForFloatCount.Inc()
+	}
+	return nil
+}
+
+// componentsToArray reads the components of a vector in the order of the keys.
+func (r *rowParser) componentsToArray(fieldID int64, keys []string, components map[string]any) ([]any, error) {
+	for key := range components {
+		if !lo.Contains(keys, key) {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("unexpected component key '%s' for field '%s', expected keys: %v",
+				key, r.id2Field[fieldID].GetName(), keys))
+		}
+	}
+	arr := make([]any, 0, len(keys))
+	for _, key := range keys {
+		value, ok := components[key]
+		if !ok {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("component key '%s' of field '%s' is missed",
+				key, r.id2Field[fieldID].GetName()))
+		}
+		arr = append(arr, value)
+	}
+	return arr, nil
+}
+
+// indexedComponentsToArray reads the components of a vector keyed by their indexes,
+// the sentinel components are filled with 0 or rejected by the sentinel policy.
+func (r *rowParser) indexedComponentsToArray(fieldID int64, components map[string]any) ([]any, error) {
+	name := r.id2Field[fieldID].GetName()
+	arr := make([]any, r.dims[fieldID])
+	for key, value := range components {
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(arr) {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("invalid component index '%s' for field '%s' with dim %d",
+				key, name, len(arr)))
+		}
+		str, isString := value.(string)
+		if value == nil || (isString && r.option.vectorSentinels.Contain(str)) {
+			if r.option.sentinelPolicy != SentinelFillZero {
+				return nil, merr.WrapErrImportFailed(fmt.Sprintf("component %d of field '%s' is %s, which is not allowed",
+					index, name, lo.Ternary(isString, "'"+str+"'", "null")))
+			}
+			value = json.Number("0")
+		}
+		arr[index] = value
+	}
+	for i, value := range arr {
+		if value == nil {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("component %d of field '%s' is missed", i, name))
+		}
+	}
+	return arr, nil
+}
+
+func (r *rowParser) parseEntity(fieldID int64, obj any) (any, error) {
+	if r.option.unwrapSingletonVector && typeutil.IsVectorType(r.id2Field[fieldID].GetDataType()) {
+		obj = unwrapSingletonArray(obj)
+	}
+	if keys, ok := r.option.vectorComponentKeys[fieldID]; ok {
+		if components, ok := obj.(map[string]any); ok {
+			arr, err := r.componentsToArray(fieldID, keys, components)
 			if err != nil {
 				return nil, err
 			}
-			row[fieldID] = data
-		} else if r.dynamicField != nil {
-			if key == r.dynamicField.GetName() {
-				return nil, merr.WrapErrImportFailed(
-					fmt.Sprintf("dynamic field is enabled, explicit specification of '%s' is not allowed", key))
-			}
-			// has dynamic field, put redundant pair to dynamicValues
-			dynamicValues[key] = value
-		} else {
-			return nil, merr.WrapErrImportFailed(fmt.Sprintf("the field '%s' is not defined in schema", key))
+			obj = arr
 		}
 	}
-	for fieldName, fieldID := range r.name2FieldID {
-		if _, ok = row[fieldID]; !ok {
-			return nil, merr.WrapErrImportFailed(fmt.Sprintf("value of field '%s' is missed", fieldName))
+	if r.option.indexKeyedVectors.Contain(fieldID) {
+		if components, ok := obj.(map[string]any); ok {
+			arr, err := r.indexedComponentsToArray(fieldID, components)
+			if err != nil {
+				return nil, err
+			}
+			obj = arr
 		}
 	}
-	if r.dynamicField == nil {
-		return row, nil
+	if encoding, ok := r.option.vectorEncodings[fieldID]; ok {
+		if str, ok := obj.(string); ok {
+			return r.parseEncodedVector(encoding, str, fieldID)
+		}
 	}
-	// combine the redundant pairs into dynamic field(if it has)
-	err := r.combineDynamicRow(dynamicValues, row)
-	if err != nil {
-		return nil, err
+	if _, ok := r.option.timestampFields[fieldID]; ok {
+		converted, err := r.convertTimestamps(obj, fieldID)
+		if err != nil {
+			return nil, err
+		}
+		obj = converted
 	}
-	return row, err
-}
-
-func (r *rowParser) combineDynamicRow(dynamicValues map[string]any, row Row) error {
-	// Combine the dynamic field value
-	// invalid inputs:
-	// case 1: {"id": 1, "vector": [], "$meta": {"x": 8}} ==>> "$meta" is not allowed
-	// valid inputs:
-	// case 2: {"id": 1, "vector": [], "x": 8} ==>> {"id": 1, "vector": [], "$meta": "{\"x\": 8}"}
-	// case 3: {"id": 1, "vector": []}
-	dynamicFieldID := r.dynamicField.GetFieldID()
-	if len(dynamicValues) > 0 {
-		// case 2
-		data, err := r.parseEntity(dynamicFieldID, dynamicValues)
+	if _, ok := r.option.scaleFields[fieldID]; ok {
+		scaled, err := r.scaleToInt(obj, fieldID)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		obj = scaled
+	}
+	if r.option.maxArrayIncludeVector && typeutil.IsVectorType(r.id2Field[fieldID].GetDataType()) {
+		if err := r.checkArrayElements(obj, fieldID); err != nil {
+			return nil, err
 		}
-		row[dynamicFieldID] = data
-	} else {
-		// case 3
-		row[dynamicFieldID] = "{}"
 	}
-	return nil
-}
-
-func (r *rowParser) parseEntity(fieldID int64, obj any) (any, error) {
 	switch r.id2Field[fieldID].GetDataType() {
 	case schemapb.DataType_Bool:
-		b, ok := obj.(bool)
+		b, ok := r.parseBool(obj)
 		if !ok {
 			return nil, r.wrapTypeError(obj, fieldID)
 		}
 		return b, nil
 	case schemapb.DataType_Int8:
-		value, ok := obj.(json.Number)
-		if !ok {
-			return nil, r.wrapTypeError(obj, fieldID)
-		}
-		num, err := strconv.ParseInt(value.String(), 0, 8)
+		num, err := r.parseInteger(obj, fieldID, 8)
 		if err != nil {
 			return nil, err
 		}
 		return int8(num), nil
 	case schemapb.DataType_Int16:
-		value, ok := obj.(json.Number)
-		if !ok {
-			return nil, r.wrapTypeError(obj, fieldID)
-		}
-		num, err := strconv.ParseInt(value.String(), 0, 16)
+		num, err := r.parseInteger(obj, fieldID, 16)
 		if err != nil {
 			return nil, err
 		}
 		return int16(num), nil
 	case schemapb.DataType_Int32:
-		value, ok := obj.(json.Number)
-		if !ok {
-			return nil, r.wrapTypeError(obj, fieldID)
-		}
-		num, err := strconv.ParseInt(value.String(), 0, 32)
+		num, err := r.parseInteger(obj, fieldID, 32)
 		if err != nil {
 			return nil, err
 		}
 		return int32(num), nil
 	case schemapb.DataType_Int64:
-		value, ok := obj.(json.Number)
-		if !ok {
-			return nil, r.wrapTypeError(obj, fieldID)
+		if str, ok := obj.(string); ok && r.option.hexStringPK && fieldID == r.pkField.GetFieldID() {
+			return r.parseHexPK(str)
 		}
-		num, err := strconv.ParseInt(value.String(), 0, 64)
+		num, err := r.parseInteger(obj, fieldID, 64)
 		if err != nil {
 			return nil, err
 		}
 		return num, nil
 	case schemapb.DataType_Float:
-		value, ok := obj.(json.Number)
-		if !ok {
-			return nil, r.wrapTypeError(obj, fieldID)
+		value, err := r.floatNumber(obj, fieldID)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.checkIntegerForFloat(value, fieldID); err != nil {
+			return nil, err
 		}
 		num, err := strconv.ParseFloat(value.String(), 32)
 		if err != nil {
@@ -223,34 +1314,35 @@ func (r *rowParser) parseEntity(fieldID int64, obj any) (any, error) {
 		}
 		return float32(num), nil
 	case schemapb.DataType_Double:
-		value, ok := obj.(json.Number)
-		if !ok {
-			return nil, r.wrapTypeError(obj, fieldID)
+		value, err := r.floatNumber(obj, fieldID)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.checkIntegerForFloat(value, fieldID); err != nil {
+			return nil, err
 		}
 		num, err := strconv.ParseFloat(value.String(), 64)
 		if err != nil {
 			return nil, err
 		}
+		if r.option.warnDoublePrecisionLoss != nil && !isFloatRoundTrip(value.String(), num) {
+			r.option.warnDoublePrecisionLoss(r.id2Field[fieldID].GetName(), value.String(), num)
+		}
 		return num, nil
 	case schemapb.DataType_BinaryVector:
 		arr, ok := obj.([]interface{})
 		if !ok {
 			return nil, r.wrapTypeError(obj, fieldID)
 		}
-		if len(arr)*8 != r.dim {
-			return nil, r.wrapDimError(len(arr)*8, fieldID)
+		if len(arr) == 0 {
+			return nil, r.wrapEmptyVectorError(fieldID)
 		}
-		vec := make([]byte, len(arr))
-		for i := 0; i < len(arr); i++ {
-			value, ok := arr[i].(json.Number)
-			if !ok {
-				return nil, r.wrapTypeError(arr[i], fieldID)
-			}
-			num, err := strconv.ParseUint(value.String(), 0, 8)
-			if err != nil {
-				return nil, err
-			}
-			vec[i] = byte(num)
+		vec, err := r.arrayToBytes(arr, fieldID)
+		if err != nil {
+			return nil, err
+		}
+		if len(vec)*8 != r.dims[fieldID] {
+			return nil, r.wrapDimError(len(vec)*8, fieldID)
 		}
 		return vec, nil
 	case schemapb.DataType_FloatVector:
@@ -258,12 +1350,28 @@ func (r *rowParser) parseEntity(fieldID int64, obj any) (any, error) {
 		if !ok {
 			return nil, r.wrapTypeError(obj, fieldID)
 		}
-		if len(arr) != r.dim {
+		if len(arr) == 0 {
+			return nil, r.wrapEmptyVectorError(fieldID)
+		}
+		// a vector with null elements is partial, which is invalid even if the vector could be null
+		if index := lo.IndexOf(arr, nil); index >= 0 {
+			field := r.id2Field[fieldID]
+			return nil, newParseError(ErrKindInvalidValue, field, nil, merr.WrapErrImportFailed(
+				fmt.Sprintf("field '%s' vector element at index %d is null", field.GetName(), index)))
+		}
+		if r.option.floatVectorFromBytes && len(arr) == 4*r.dims[fieldID] {
+			if logger := r.option.logger; logger != nil {
+				logger.Debug("coerce byte array to float vector",
+					zap.String("field", r.id2Field[fieldID].GetName()), zap.Int("numBytes", len(arr)))
+			}
+			return r.bytesToFloatVector(arr, fieldID)
+		}
+		if len(arr) != r.dims[fieldID] {
 			return nil, r.wrapDimError(len(arr), fieldID)
 		}
 		vec := make([]float32, len(arr))
 		for i := 0; i < len(arr); i++ {
-			value, ok := arr[i].(json.Number)
+			value, ok := r.asNumber(arr[i])
 			if !ok {
 				return nil, r.wrapTypeError(arr[i], fieldID)
 			}
@@ -273,26 +1381,25 @@ func (r *rowParser) parseEntity(fieldID int64, obj any) (any, error) {
 			}
 			vec[i] = float32(num)
 		}
+		r.checkIntegerFloatVector(arr, fieldID)
 		return vec, nil
-	case schemapb.DataType_Float16Vector:
+	case schemapb.DataType_Float16Vector, schemapb.DataType_BFloat16Vector:
 		arr, ok := obj.([]interface{})
 		if !ok {
 			return nil, r.wrapTypeError(obj, fieldID)
 		}
-		if len(arr)/2 != r.dim {
-			return nil, r.wrapDimError(len(arr)/2, fieldID)
+		if len(arr) == 0 {
+			return nil, r.wrapEmptyVectorError(fieldID)
 		}
-		vec := make([]byte, len(arr))
-		for i := 0; i < len(arr); i++ {
-			value, ok := arr[i].(json.Number)
-			if !ok {
-				return nil, r.wrapTypeError(arr[i], fieldID)
-			}
-			num, err := strconv.ParseUint(value.String(), 0, 8)
-			if err != nil {
-				return nil, err
-			}
-			vec[i] = byte(num)
+		if r.option.halfFloatFields.Contain(fieldID) && len(arr) == r.dims[fieldID] {
+			return r.floatsToHalfVector(arr, fieldID)
+		}
+		vec, err := r.arrayToBytes(arr, fieldID)
+		if err != nil {
+			return nil, err
+		}
+		if len(vec)/2 != r.dims[fieldID] {
+			return nil, r.wrapDimError(len(vec)/2, fieldID)
 		}
 		return vec, nil
 	case schemapb.DataType_String, schemapb.DataType_VarChar:
@@ -300,6 +1407,31 @@ func (r *rowParser) parseEntity(fieldID int64, obj any) (any, error) {
 		if !ok {
 			return nil, r.wrapTypeError(obj, fieldID)
 		}
+		if r.option.stripBOM {
+			value = strings.TrimPrefix(value, "\uFEFF")
+		}
+		if r.option.rejectEmptyStringPK && value == "" && fieldID == r.pkField.GetFieldID() {
+			return nil, newParseError(ErrKindInvalidValue, r.pkField, nil, merr.WrapErrImportFailed(
+				fmt.Sprintf("the primary key '%s' is an empty string, which is likely a blank id in the source data", r.pkField.GetName())))
+		}
+		if r.option.jsonStringFields.Contain(fieldID) && !json.Valid([]byte(value)) {
+			return nil, newParseError(ErrKindInvalidValue, r.id2Field[fieldID], value, merr.WrapErrImportFailed(
+				fmt.Sprintf("value of field '%s' is not valid JSON: '%s'", r.id2Field[fieldID].GetName(), value)))
+		}
+		if err := r.checkVarCharBytes(value, fieldID, -1); err != nil {
+			return nil, err
+		}
+		if r.option.asciiOnlyFields.Contain(fieldID) {
+			if err := r.checkASCII(value, fieldID); err != nil {
+				return nil, err
+			}
+		}
+		if newHash, ok := r.hashFuncs[fieldID]; ok {
+			value = hashString(newHash, value)
+			if err := r.checkMaxLength(value, fieldID); err != nil {
+				return nil, err
+			}
+		}
 		return value, nil
 	case schemapb.DataType_JSON:
 		// for JSON data, we accept two kinds input: string and map[string]interface
@@ -310,25 +1442,77 @@ func (r *rowParser) parseEntity(fieldID int64, obj any) (any, error) {
 			if err != nil {
 				return nil, err
 			}
-			return []byte(value), nil
-		} else if mp, ok := obj.(map[string]interface{}); ok {
-			bs, err := json.Marshal(mp)
-			if err != nil {
+			if err = r.checkJSONKeys(dummy, fieldID); err != nil {
 				return nil, err
 			}
-			return bs, nil
-		} else {
+			if r.option.canonicalJSONFields {
+				return canonicalizeJSONString(value)
+			}
+			return []byte(value), nil
+		}
+		switch obj.(type) {
+		case map[string]any, map[any]any:
+		default:
 			return nil, r.wrapTypeError(obj, fieldID)
 		}
+		normalized, err := normalizeJSONValue(obj, "$")
+		if err != nil {
+			return nil, newParseError(ErrKindInvalidValue, r.id2Field[fieldID], nil, merr.WrapErrImportFailed(
+				fmt.Sprintf("invalid value of JSON field '%s', error: %v", r.id2Field[fieldID].GetName(), err)))
+		}
+		mp := normalized.(map[string]any)
+		if err := r.checkJSONKeys(mp, fieldID); err != nil {
+			return nil, err
+		}
+		if r.option.canonicalJSONFields {
+			return json.Marshal(canonicalizeJSONValue(mp))
+		}
+		return json.Marshal(mp)
 	case schemapb.DataType_Array:
+		if str, ok := obj.(string); ok && r.option.arrayFromJSONString {
+			decoded, err := r.decodeArrayString(str, fieldID)
+			if err != nil {
+				return nil, err
+			}
+			obj = decoded
+		}
+		if _, ok := r.option.bitfields[fieldID]; ok {
+			if num, ok := r.asNumber(obj); ok {
+				unpacked, err := r.unpackBitfield(num.String(), fieldID)
+				if err != nil {
+					return nil, err
+				}
+				obj = unpacked
+			}
+		}
 		arr, ok := obj.([]interface{})
 		if !ok {
 			return nil, r.wrapTypeError(obj, fieldID)
 		}
-		scalarFieldData, err := r.arrayToFieldData(arr, r.id2Field[fieldID].GetElementType())
+		if err := r.checkArrayElements(arr, fieldID); err != nil {
+			return nil, err
+		}
+		// null elements are not representable since array elements are not nullable
+		if index := lo.IndexOf(arr, nil); index >= 0 {
+			field := r.id2Field[fieldID]
+			return nil, newParseError(ErrKindInvalidValue, field, nil, merr.WrapErrImportFailed(
+				fmt.Sprintf("null elements not allowed in Array<%s>, field '%s' has null at index %d",
+					field.GetElementType().String(), field.GetName(), index)))
+		}
+		scalarFieldData, err := r.arrayToFieldData(arr, fieldID)
 		if err != nil {
 			return nil, err
 		}
+		if r.option.dedupArrayFields.Contain(fieldID) {
+			dedupArrayElements(scalarFieldData)
+		}
+		// the elements dropped by the option are not counted, so it's checked after conversion
+		if capacity, ok := r.capacities[fieldID]; ok && r.option.dropEmptyArrayFields.Contain(fieldID) &&
+			arrayLen(scalarFieldData) > capacity {
+			return nil, newParseError(ErrKindInvalidValue, r.id2Field[fieldID], nil, merr.WrapErrImportFailed(
+				fmt.Sprintf("the array of field '%s' has %d elements, exceeds the max_capacity %d",
+					r.id2Field[fieldID].GetName(), arrayLen(scalarFieldData), capacity)))
+		}
 		return scalarFieldData, nil
 	default:
 		return nil, merr.WrapErrImportFailed(fmt.Sprintf("parse json failed, unsupport data type: %s",
@@ -336,12 +1520,372 @@ func (r *rowParser) parseEntity(fieldID int64, obj any) (any, error) {
 	}
 }
 
-func (r *rowParser) arrayToFieldData(arr []interface{}, eleType schemapb.DataType) (*schemapb.ScalarField, error) {
+// parseBool accepts a bool, or one of the configured truthy and falsy strings.
+func (r *rowParser) parseBool(obj any) (bool, bool) {
+	switch v := obj.(type) {
+	case bool:
+		return v, true
+	case string:
+		str := strings.ToLower(v)
+		if r.option.truthyStrings.Contain(str) {
+			return true, true
+		}
+		if r.option.falsyStrings.Contain(str) {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// parseInteger parses an integer value with the given bit size.
+func (r *rowParser) parseInteger(obj any, fieldID int64, bitSize int) (int64, error) {
+	if b, ok := obj.(bool); ok && r.option.boolAsInteger {
+		// true => 1, false => 0
+		if logger := r.option.logger; logger != nil {
+			logger.Debug("coerce bool to integer",
+				zap.String("field", r.id2Field[fieldID].GetName()), zap.Bool("value", b))
+		}
+		if b {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	value, ok := r.asNumber(obj)
+	if !ok {
+		return 0, r.wrapTypeError(obj, fieldID)
+	}
+	str := value.String()
+	if integer, fraction, found := strings.Cut(str, "."); found {
+		if r.option.strictIntegerFields.Contain(fieldID) {
+			return 0, newParseError(ErrKindInvalidValue, r.id2Field[fieldID], value, merr.WrapErrImportFailed(
+				fmt.Sprintf("field '%s' requires an integer, got '%s' written with a decimal point", r.id2Field[fieldID].GetName(), str)))
+		}
+		if r.option.integralDecimals && strings.Trim(fraction, "0") == "" {
+			str = integer
+		}
+	}
+	num, err := strconv.ParseInt(str, r.option.integerBase, bitSize)
+	// ParseInt returns the min or max of the bit size on range error
+	if errors.Is(err, strconv.ErrRange) && bitSize < 64 && r.option.clampIntegerFields.Contain(fieldID) {
+		if r.option.onClamp != nil {
+			r.option.onClamp(r.id2Field[fieldID].GetName(), str, num)
+		}
+		return num, nil
+	}
+	return num, err
+}
+
+// parseHexPK parses the primary key written as a hexadecimal string with the "0x" prefix.
+func (r *rowParser) parseHexPK(str string) (int64, error) {
+	if len(str) < 3 || str[0] != '0' || (str[1] != 'x' && str[1] != 'X') {
+		return 0, newParseError(ErrKindInvalidValue, r.pkField, str, merr.WrapErrImportFailed(
+			fmt.Sprintf("the primary key '%s' must be a hexadecimal string with the '0x' prefix, got '%s'", r.pkField.GetName(), str)))
+	}
+	num, err := strconv.ParseInt(str[2:], 16, 64)
+	if err != nil {
+		return 0, newParseError(ErrKindInvalidValue, r.pkField, str, merr.WrapErrImportFailed(
+			fmt.Sprintf("the primary key '%s' is not a valid hexadecimal int64 '%s', error: %v", r.pkField.GetName(), str, err)))
+	}
+	return num, nil
+}
+
+// checkIntegerFloatVector reports a float vector whose components are all written as integers,
+// which is unexpected unless the field is designated to hold such vectors, e.g. one-hot vectors.
+func (r *rowParser) checkIntegerFloatVector(arr []interface{}, fieldID int64) {
+	if r.option.warnIntegerFloatVector == nil && r.option.integerFloatVectorFields.Len() == 0 {
+		return
+	}
+	for _, v := range arr {
+		if value, _ := r.asNumber(v); strings.ContainsAny(value.String(), ".eE") {
+			return
+		}
+	}
+	field := r.id2Field[fieldID]
+	if r.option.integerFloatVectorFields.Contain(fieldID) {
+		if logger := r.option.logger; logger != nil {
+			logger.Debug("integer float vector treated as floats", zap.String("field", field.GetName()))
+		}
+		return
+	}
+	if r.option.warnIntegerFloatVector != nil {
+		r.option.warnIntegerFloatVector(field.GetName())
+	}
+}
+
+// isFloatRoundTrip checks whether the decimal string has the same value as
+// the shortest decimal representation of the parsed float64.
+func isFloatRoundTrip(str string, num float64) bool {
+	const prec = 256
+	expect, _, err := big.ParseFloat(str, 10, prec, big.ToNearestEven)
+	if err != nil {
+		return false
+	}
+	actual, _, err := big.ParseFloat(strconv.FormatFloat(num, 'g', -1, 64), 10, prec, big.ToNearestEven)
+	if err != nil {
+		return false
+	}
+	return expect.Cmp(actual) == 0
+}
+
+// checkMaxLength checks the value against the max_length of the field, if it is declared.
+func (r *rowParser) checkMaxLength(value string, fieldID int64) error {
+	field := r.id2Field[fieldID]
+	maxLength, err := parameterutil.GetMaxLength(field)
+	if err != nil {
+		return nil
+	}
+	if int64(len(value)) > maxLength {
+		return merr.WrapErrImportFailed(fmt.Sprintf("the length %d of value for field '%s' exceeds max_length %d",
+			len(value), field.GetName(), maxLength))
+	}
+	return nil
+}
+
+// checkVarCharBytes checks the value, or the element at index of an array if index is not -1,
+// against the stricter of the global limit and the max_length of the field. It's only checked
+// if the global limit is set, otherwise the max_length is left to the insertion.
+func (r *rowParser) checkVarCharBytes(value string, fieldID int64, index int) error {
+	if r.option.maxVarCharBytes <= 0 {
+		return nil
+	}
+	limit, fromSchema := r.option.maxVarCharBytes, false
+	if maxLength, ok := r.maxLengths[fieldID]; ok && maxLength <= limit {
+		limit, fromSchema = maxLength, true
+	}
+	if len(value) <= limit {
+		return nil
+	}
+	field := r.id2Field[fieldID]
+	target := fmt.Sprintf("value for field '%s'", field.GetName())
+	if index >= 0 {
+		target = fmt.Sprintf("element at index %d for field '%s'", index, field.GetName())
+	}
+	if fromSchema {
+		return newParseError(ErrKindInvalidValue, field, nil, merr.WrapErrImportFailed(
+			fmt.Sprintf("the length %d of %s exceeds max_length %d of the schema", len(value), target, limit)))
+	}
+	return newParseError(ErrKindInvalidValue, field, nil, merr.WrapErrImportFailed(
+		fmt.Sprintf("the length %d of %s exceeds the global limit %d bytes of VarChar values", len(value), target, limit)))
+}
+
+// checkNumericRange checks the parsed value of a numeric field against the configured range.
+func (r *rowParser) checkNumericRange(data any, fieldID int64) error {
+	rng, ok := r.option.numericRanges[fieldID]
+	if !ok {
+		return nil
+	}
+	var value float64
+	switch v := data.(type) {
+	case int8:
+		value = float64(v)
+	case int16:
+		value = float64(v)
+	case int32:
+		value = float64(v)
+	case int64:
+		value = float64(v)
+	case float32:
+		value = float64(v)
+	case float64:
+		value = v
+	default:
+		return nil
+	}
+	if value < rng.Min || value > rng.Max || math.IsNaN(value) {
+		field := r.id2Field[fieldID]
+		return newParseError(ErrKindInvalidValue, field, data, merr.WrapErrImportFailed(
+			fmt.Sprintf("value %v of field '%s' is out of range [%v, %v]", data, field.GetName(), rng.Min, rng.Max)))
+	}
+	return nil
+}
+
+// checkComponentRange checks each component of the parsed FloatVector against the configured range.
+func (r *rowParser) checkComponentRange(data any, fieldID int64) error {
+	rng, ok := r.option.componentRanges[fieldID]
+	if !ok {
+		return nil
+	}
+	vec, _ := data.([]float32)
+	for i, v := range vec {
+		if value := float64(v); value < rng.Min || value > rng.Max {
+			field := r.id2Field[fieldID]
+			return newParseError(ErrKindInvalidValue, field, v, merr.WrapErrImportFailed(
+				fmt.Sprintf("component %v at index %d of field '%s' is out of range [%v, %v]", v, i, field.GetName(), rng.Min, rng.Max)))
+		}
+	}
+	return nil
+}
+
+// checkAllowedValue checks the parsed value of a VarChar or integer field against the allowed values.
+func (r *rowParser) checkAllowedValue(data any, fieldID int64) error {
+	allowed, ok := r.allowed[fieldID]
+	if !ok {
+		return nil
+	}
+	if value, ok := normalizeAllowedValue(data); ok && allowed.Contain(value) {
+		return nil
+	}
+	field := r.id2Field[fieldID]
+	return newParseError(ErrKindInvalidValue, field, data, merr.WrapErrImportFailed(
+		fmt.Sprintf("value '%v' of field '%s' is not one of the allowed values %v", data, field.GetName(), r.option.allowedValues[fieldID])))
+}
+
+// checkJSONKeys checks the top-level keys of the JSON object against the allowlist of the field.
+func (r *rowParser) checkJSONKeys(value any, fieldID int64) error {
+	allowlist, ok := r.option.jsonKeyAllowlists[fieldID]
+	if !ok {
+		return nil
+	}
+	mp, ok := value.(map[string]any)
+	if !ok {
+		return merr.WrapErrImportFailed(fmt.Sprintf("field '%s' expects a JSON object, got '%v'",
+			r.id2Field[fieldID].GetName(), value))
+	}
+	for key := range mp {
+		if !allowlist.Contain(key) {
+			allowed := allowlist.Collect()
+			sort.Strings(allowed)
+			return merr.WrapErrImportFailed(fmt.Sprintf("unexpected key '%s' in JSON field '%s', allowed keys: %v",
+				key, r.id2Field[fieldID].GetName(), allowed))
+		}
+	}
+	return nil
+}
+
+// checkArrayElements checks the number of elements against the global limit.
+func (r *rowParser) checkArrayElements(obj any, fieldID int64) error {
+	arr, ok := obj.([]any)
+	if !ok || r.option.maxArrayElements <= 0 || len(arr) <= r.option.maxArrayElements {
+		return nil
+	}
+	return merr.WrapErrImportFailed(fmt.Sprintf("the array of field '%s' has %d elements, exceeds the limit %d",
+		r.id2Field[fieldID].GetName(), len(arr), r.option.maxArrayElements))
+}
+
+// checkASCII reports the offset of the first non-ASCII byte of the value.
+func (r *rowParser) checkASCII(value string, fieldID int64) error {
+	for i := 0; i < len(value); i++ {
+		if value[i] > unicode.MaxASCII {
+			return merr.WrapErrImportFailed(fmt.Sprintf("value of field '%s' must be ASCII only, got non-ASCII byte 0x%02x at offset %d",
+				r.id2Field[fieldID].GetName(), value[i], i))
+		}
+	}
+	return nil
+}
+
+// decodeArrayString decodes a JSON-encoded array such as "[1, 2, 3]".
+func (r *rowParser) decodeArrayString(str string, fieldID int64) ([]interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(str))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("failed to decode JSON string for array field '%s', error: %v",
+			r.id2Field[fieldID].GetName(), err))
+	}
+	arr, ok := value.([]interface{})
+	if !ok {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("the JSON string for array field '%s' is not an array, got '%s'",
+			r.id2Field[fieldID].GetName(), str))
+	}
+	return arr, nil
+}
+
+// unwrapSingletonArray unwraps a vector wrapped in an extra array level, e.g. [[0.1, 0.2]].
+func unwrapSingletonArray(obj any) any {
+	arr, ok := obj.([]interface{})
+	if !ok || len(arr) != 1 {
+		return obj
+	}
+	if inner, ok := arr[0].([]interface{}); ok {
+		return inner
+	}
+	return obj
+}
+
+// arrayToBytes converts an array of byte values, such as the content
+// of a binary or float16 vector, into a byte slice.
+func (r *rowParser) arrayToBytes(arr []interface{}, fieldID int64) ([]byte, error) {
+	vec := make([]byte, len(arr))
+	for i := 0; i < len(arr); i++ {
+		value, ok := r.asNumber(arr[i])
+		if !ok {
+			return nil, r.wrapTypeError(arr[i], fieldID)
+		}
+		num, err := strconv.ParseUint(value.String(), 0, 8)
+		if err != nil {
+			return nil, r.wrapByteValueError(value, i, fieldID)
+		}
+		vec[i] = byte(num)
+	}
+	return vec, nil
+}
+
+// bytesToFloatVector reinterprets an array of 4*dim byte values as dim float32 values.
+func (r *rowParser) bytesToFloatVector(arr []interface{}, fieldID int64) ([]float32, error) {
+	bytes, err := r.arrayToBytes(arr, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	order := r.byteOrder(fieldID)
+	vec := make([]float32, len(bytes)/4)
+	for i := range vec {
+		vec[i] = math.Float32frombits(order.Uint32(bytes[i*4:]))
+	}
+	return vec, nil
+}
+
+// byteOrder returns the byte order of the float32 bytes of the field.
+func (r *rowParser) byteOrder(fieldID int64) binary.ByteOrder {
+	if order, ok := r.option.fieldByteOrders[fieldID]; ok {
+		return order
+	}
+	return r.option.floatVectorByteOrder
+}
+
+// dedupArrayElements removes the duplicated elements, the first-seen order is preserved.
+func dedupArrayElements(data *schemapb.ScalarField) {
+	switch d := data.GetData().(type) {
+	case *schemapb.ScalarField_BoolData:
+		d.BoolData.Data = lo.Uniq(d.BoolData.GetData())
+	case *schemapb.ScalarField_IntData:
+		d.IntData.Data = lo.Uniq(d.IntData.GetData())
+	case *schemapb.ScalarField_LongData:
+		d.LongData.Data = lo.Uniq(d.LongData.GetData())
+	case *schemapb.ScalarField_FloatData:
+		d.FloatData.Data = lo.Uniq(d.FloatData.GetData())
+	case *schemapb.ScalarField_DoubleData:
+		d.DoubleData.Data = lo.Uniq(d.DoubleData.GetData())
+	case *schemapb.ScalarField_StringData:
+		d.StringData.Data = lo.Uniq(d.StringData.GetData())
+	}
+}
+
+func arrayLen(data *schemapb.ScalarField) int {
+	switch d := data.GetData().(type) {
+	case *schemapb.ScalarField_BoolData:
+		return len(d.BoolData.GetData())
+	case *schemapb.ScalarField_IntData:
+		return len(d.IntData.GetData())
+	case *schemapb.ScalarField_LongData:
+		return len(d.LongData.GetData())
+	case *schemapb.ScalarField_FloatData:
+		return len(d.FloatData.GetData())
+	case *schemapb.ScalarField_DoubleData:
+		return len(d.DoubleData.GetData())
+	case *schemapb.ScalarField_StringData:
+		return len(d.StringData.GetData())
+	default:
+		return 0
+	}
+}
+
+func (r *rowParser) arrayToFieldData(arr []interface{}, fieldID int64) (*schemapb.ScalarField, error) {
+	eleType := r.id2Field[fieldID].GetElementType()
 	switch eleType {
 	case schemapb.DataType_Bool:
 		values := make([]bool, 0)
 		for i := 0; i < len(arr); i++ {
-			value, ok := arr[i].(bool)
+			value, ok := r.parseBool(arr[i])
 			if !ok {
 				return nil, r.wrapArrayValueTypeError(arr, eleType)
 			}
@@ -355,13 +1899,24 @@ func (r *rowParser) arrayToFieldData(arr []interface{}, eleType schemapb.DataTyp
 			},
 		}, nil
 	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32:
+		bitSize := 32
+		if eleType == schemapb.DataType_Int8 {
+			bitSize = 8
+		} else if eleType == schemapb.DataType_Int16 {
+			bitSize = 16
+		}
 		values := make([]int32, 0)
 		for i := 0; i < len(arr); i++ {
-			value, ok := arr[i].(json.Number)
+			value, ok := r.asNumber(arr[i])
 			if !ok {
 				return nil, r.wrapArrayValueTypeError(arr, eleType)
 			}
-			num, err := strconv.ParseInt(value.String(), 0, 32)
+			num, err := strconv.ParseInt(value.String(), r.option.integerBase, bitSize)
+			if errors.Is(err, strconv.ErrRange) {
+				field := r.id2Field[fieldID]
+				return nil, newParseError(ErrKindInvalidValue, field, value, merr.WrapErrImportFailed(fmt.Sprintf(
+					"value '%s' at index %d is out of range for element type '%s' for field '%s'", value, i, eleType.String(), field.GetName())))
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -377,11 +1932,11 @@ func (r *rowParser) arrayToFieldData(arr []interface{}, eleType schemapb.DataTyp
 	case schemapb.DataType_Int64:
 		values := make([]int64, 0)
 		for i := 0; i < len(arr); i++ {
-			value, ok := arr[i].(json.Number)
+			value, ok := r.asNumber(arr[i])
 			if !ok {
 				return nil, r.wrapArrayValueTypeError(arr, eleType)
 			}
-			num, err := strconv.ParseInt(value.String(), 0, 64)
+			num, err := strconv.ParseInt(value.String(), r.option.integerBase, 64)
 			if err != nil {
 				return nil, err
 			}
@@ -397,7 +1952,7 @@ func (r *rowParser) arrayToFieldData(arr []interface{}, eleType schemapb.DataTyp
 	case schemapb.DataType_Float:
 		values := make([]float32, 0)
 		for i := 0; i < len(arr); i++ {
-			value, ok := arr[i].(json.Number)
+			value, ok := r.asNumber(arr[i])
 			if !ok {
 				return nil, r.wrapArrayValueTypeError(arr, eleType)
 			}
@@ -417,7 +1972,7 @@ func (r *rowParser) arrayToFieldData(arr []interface{}, eleType schemapb.DataTyp
 	case schemapb.DataType_Double:
 		values := make([]float64, 0)
 		for i := 0; i < len(arr); i++ {
-			value, ok := arr[i].(json.Number)
+			value, ok := r.asNumber(arr[i])
 			if !ok {
 				return nil, r.wrapArrayValueTypeError(arr, eleType)
 			}
@@ -441,6 +1996,15 @@ func (r *rowParser) arrayToFieldData(arr []interface{}, eleType schemapb.DataTyp
 			if !ok {
 				return nil, r.wrapArrayValueTypeError(arr, eleType)
 			}
+			if r.option.trimArrayFields.Contain(fieldID) {
+				value = strings.TrimSpace(value)
+			}
+			if value == "" && r.option.dropEmptyArrayFields.Contain(fieldID) {
+				continue
+			}
+			if err := r.checkVarCharBytes(value, fieldID, i); err != nil {
+				return nil, err
+			}
 			values = append(values, value)
 		}
 		return &schemapb.ScalarField{
@@ -0,0 +1,1959 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+)
+
+func newTestSchema(fields ...*schemapb.FieldSchema) *schemapb.CollectionSchema {
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{
+				FieldID:      100,
+				Name:         "id",
+				IsPrimaryKey: true,
+				DataType:     schemapb.DataType_Int64,
+			},
+			{
+				FieldID:  101,
+				Name:     "vector",
+				DataType: schemapb.DataType_FloatVector,
+				TypeParams: []*commonpb.KeyValuePair{
+					{
+						Key:   common.DimKey,
+						Value: "2",
+					},
+				},
+			},
+		},
+	}
+	schema.Fields = append(schema.Fields, fields...)
+	return schema
+}
+
+func decodeRow(t *testing.T, str string) any {
+	dec := json.NewDecoder(strings.NewReader(str))
+	dec.UseNumber()
+	var value any
+	err := dec.Decode(&value)
+	assert.NoError(t, err)
+	return value
+}
+
+func TestRowParser_PartitionKey(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{
+		FieldID:        102,
+		Name:           "tenant",
+		DataType:       schemapb.DataType_VarChar,
+		IsPartitionKey: true,
+	})
+	parser, err := NewRowParser(schema)
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "tenant": "a"}`))
+	assert.NoError(t, err)
+	key, err := parser.PartitionKeyOf(row)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", key)
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2]}`))
+	assert.ErrorContains(t, err, "partition key field 'tenant' is missed")
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "tenant": null}`))
+	assert.ErrorContains(t, err, "partition key field 'tenant' is missed")
+
+	parser, err = NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	_, err = parser.PartitionKeyOf(row)
+	assert.Error(t, err)
+}
+
+func TestRowParser_FloatVectorFromBytes(t *testing.T) {
+	toByteArray := func(order binary.ByteOrder, values ...float32) string {
+		bytes := make([]byte, 4*len(values))
+		for i, v := range values {
+			order.PutUint32(bytes[i*4:], math.Float32bits(v))
+		}
+		strs := make([]string, 0, len(bytes))
+		for _, b := range bytes {
+			strs = append(strs, fmt.Sprint(b))
+		}
+		return "[" + strings.Join(strs, ",") + "]"
+	}
+
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		parser, err := NewRowParser(newTestSchema(), WithFloatVectorFromBytes(order))
+		assert.NoError(t, err)
+		row, err := parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": 1, "vector": %s}`, toByteArray(order, 0.5, -1.25))))
+		assert.NoError(t, err)
+		assert.Equal(t, []float32{0.5, -1.25}, row[101])
+
+		// the normal float array form is still accepted
+		row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.5, -1.25]}`))
+		assert.NoError(t, err)
+		assert.Equal(t, []float32{0.5, -1.25}, row[101])
+	}
+
+	// decoding with the wrong byte order gives different values
+	parser, err := NewRowParser(newTestSchema(), WithFloatVectorFromBytes(binary.BigEndian))
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": 1, "vector": %s}`, toByteArray(binary.LittleEndian, 0.5, -1.25))))
+	assert.NoError(t, err)
+	assert.NotEqual(t, []float32{0.5, -1.25}, row[101])
+
+	// byte values must be in [0, 255]
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0, 0, 0, 256, 0, 0, 0, 0]}`))
+	assert.Error(t, err)
+
+	// without the option, the byte form is a dim mismatch
+	parser, err = NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": 1, "vector": %s}`, toByteArray(binary.LittleEndian, 0.5, -1.25))))
+	assert.ErrorContains(t, err, "expected dim")
+}
+
+func TestRowParser_ByteOrder(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{
+		FieldID:    102,
+		Name:       "vector2",
+		DataType:   schemapb.DataType_FloatVector,
+		TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}},
+	})
+	toBytes := func(order binary.ByteOrder, values ...float32) []byte {
+		bytes := make([]byte, 4*len(values))
+		for i, v := range values {
+			order.PutUint32(bytes[i*4:], math.Float32bits(v))
+		}
+		return bytes
+	}
+	toByteArray := func(bytes []byte) string {
+		strs := lo.Map(bytes, func(b byte, _ int) string { return fmt.Sprint(b) })
+		return "[" + strings.Join(strs, ",") + "]"
+	}
+
+	// the field overrides the byte order of the byte arrays
+	parser, err := NewRowParser(schema, WithFloatVectorFromBytes(binary.LittleEndian), WithByteOrder(102, binary.BigEndian))
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": 1, "vector": %s, "vector2": %s}`,
+		toByteArray(toBytes(binary.LittleEndian, 0.5, -1.25)), toByteArray(toBytes(binary.BigEndian, 0.5, -1.25)))))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.5, -1.25}, row[101])
+	assert.Equal(t, []float32{0.5, -1.25}, row[102])
+
+	// and of the encoded strings
+	parser, err = NewRowParser(schema, WithVectorEncoding(VectorEncodingBase64, 101, 102), WithByteOrder(101, binary.BigEndian))
+	assert.NoError(t, err)
+	row, err = parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": 1, "vector": "%s", "vector2": "%s"}`,
+		base64.StdEncoding.EncodeToString(toBytes(binary.BigEndian, 0.5, -1.25)),
+		base64.StdEncoding.EncodeToString(toBytes(binary.LittleEndian, 0.5, -1.25)))))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.5, -1.25}, row[101])
+	assert.Equal(t, []float32{0.5, -1.25}, row[102])
+
+	// the length of the bytes is still checked
+	_, err = parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": 1, "vector": "%s", "vector2": "%s"}`,
+		base64.StdEncoding.EncodeToString(toBytes(binary.BigEndian, 0.5)),
+		base64.StdEncoding.EncodeToString(toBytes(binary.LittleEndian, 0.5, -1.25)))))
+	assert.ErrorContains(t, err, "expected dim")
+
+	_, err = NewRowParser(schema, WithByteOrder(100, binary.BigEndian))
+	assert.ErrorContains(t, err, "byte order is only supported for FloatVector field")
+}
+
+func TestRowParser_Logger(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{
+		FieldID:   102,
+		Name:      "$meta",
+		DataType:  schemapb.DataType_JSON,
+		IsDynamic: true,
+	})
+	core, logs := observer.New(zapcore.DebugLevel)
+	parser, err := NewRowParser(schema, WithLogger(zap.New(core)), WithFloatVectorFromBytes(binary.LittleEndian))
+	assert.NoError(t, err)
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0, 0, 0, 0, 0, 0, 0, 0]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, logs.Len())
+	assert.Equal(t, 1, logs.FilterMessage("coerce byte array to float vector").FilterField(zap.String("field", "vector")).Len())
+	assert.Equal(t, 1, logs.FilterField(zap.String("field", "$meta")).Len())
+
+	// no logger, no log
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+}
+
+func TestRowParser_BoolAsInteger(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "i8", DataType: schemapb.DataType_Int8},
+		&schemapb.FieldSchema{FieldID: 103, Name: "i16", DataType: schemapb.DataType_Int16},
+		&schemapb.FieldSchema{FieldID: 104, Name: "i32", DataType: schemapb.DataType_Int32},
+		&schemapb.FieldSchema{FieldID: 105, Name: "i64", DataType: schemapb.DataType_Int64},
+		&schemapb.FieldSchema{FieldID: 106, Name: "f", DataType: schemapb.DataType_Float},
+	)
+	parser, err := NewRowParser(schema, WithBoolAsInteger())
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "i8": true, "i16": false, "i32": true, "i64": false, "f": 1}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int8(1), row[102])
+	assert.Equal(t, int16(0), row[103])
+	assert.Equal(t, int32(1), row[104])
+	assert.Equal(t, int64(0), row[105])
+
+	// non-integer fields still reject booleans
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "i8": 1, "i16": 1, "i32": 1, "i64": 1, "f": true}`))
+	assert.ErrorContains(t, err, "expected type 'Float'")
+
+	// booleans are rejected without the option
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "i8": true, "i16": 1, "i32": 1, "i64": 1, "f": 1}`))
+	assert.ErrorContains(t, err, "expected type 'Int8'")
+}
+
+func TestRowParser_FieldType(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "tags", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_VarChar},
+		&schemapb.FieldSchema{FieldID: 103, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+	)
+	schema.Fields[0].AutoID = true
+	parser, err := NewRowParser(schema)
+	assert.NoError(t, err)
+
+	dt, ok := parser.FieldType("vector")
+	assert.True(t, ok)
+	assert.Equal(t, schemapb.DataType_FloatVector, dt)
+	dt, ok = parser.FieldType("tags")
+	assert.True(t, ok)
+	assert.Equal(t, schemapb.DataType_Array, dt)
+	dt, ok = parser.FieldElementType("tags")
+	assert.True(t, ok)
+	assert.Equal(t, schemapb.DataType_VarChar, dt)
+
+	// not an array field
+	_, ok = parser.FieldElementType("vector")
+	assert.False(t, ok)
+	// auto-generated primary key and dynamic field cannot be provided
+	_, ok = parser.FieldType("id")
+	assert.False(t, ok)
+	_, ok = parser.FieldType("$meta")
+	assert.False(t, ok)
+	_, ok = parser.FieldType("unknown")
+	assert.False(t, ok)
+
+	// but they have ids
+	fieldID, ok := parser.FieldID("id")
+	assert.True(t, ok)
+	assert.Equal(t, int64(100), fieldID)
+	fieldID, ok = parser.FieldID("$meta")
+	assert.True(t, ok)
+	assert.Equal(t, int64(103), fieldID)
+	_, ok = parser.FieldID("unknown")
+	assert.False(t, ok)
+}
+
+func TestRowParser_EmptyVector(t *testing.T) {
+	for _, dt := range []schemapb.DataType{schemapb.DataType_FloatVector, schemapb.DataType_BinaryVector, schemapb.DataType_Float16Vector} {
+		schema := newTestSchema()
+		schema.Fields[1].DataType = dt
+		schema.Fields[1].TypeParams[0].Value = "8"
+		parser, err := NewRowParser(schema)
+		assert.NoError(t, err)
+		_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": []}`))
+		assert.ErrorContains(t, err, "empty vector for field 'vector'")
+		assert.ErrorContains(t, err, "may be missing or null")
+	}
+}
+
+func TestRowParser_EmptyDynamicField(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true})
+	parser, err := NewRowParser(schema)
+	assert.NoError(t, err)
+
+	// the default value has the same type as the other values of the dynamic field,
+	// which is the type the JSON field data accepts
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("{}"), row[102])
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "x": 1}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"x":1}`), row[102])
+}
+
+func TestRowParser_ParseColumnar(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "name", DataType: schemapb.DataType_VarChar},
+		&schemapb.FieldSchema{FieldID: 103, Name: "tags", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_Int64},
+		&schemapb.FieldSchema{FieldID: 104, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+	)
+	parser, err := NewRowParser(schema)
+	assert.NoError(t, err)
+
+	raws := []any{
+		decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": "a", "tags": [1, 2]}`),
+		decodeRow(t, `{"id": 2, "vector": [0.3, 0.4], "name": "b", "tags": [], "x": 8}`),
+	}
+	columns := NewColumnBuffers()
+	err = parser.ParseColumnar(raws, columns)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, columns.NumRows)
+	assert.Equal(t, []int64{1, 2}, columns.Int64[100])
+	assert.Equal(t, [][]float32{{0.1, 0.2}, {0.3, 0.4}}, columns.FloatVector[101])
+	assert.Equal(t, []string{"a", "b"}, columns.String[102])
+	assert.Equal(t, 2, len(columns.Array[103]))
+	assert.Equal(t, []int64{1, 2}, columns.Array[103][0].GetLongData().GetData())
+	assert.Equal(t, [][]byte{[]byte("{}"), []byte(`{"x":8}`)}, columns.Bytes[104])
+
+	// an invalid row is not appended
+	err = parser.ParseColumnar([]any{decodeRow(t, `{"id": 3, "vector": [0.1, 0.2]}`)}, columns)
+	assert.ErrorContains(t, err, "failed to parse row 0")
+	assert.Equal(t, 2, columns.NumRows)
+	assert.Equal(t, 2, len(columns.Int64[100]))
+
+	// the values appended before the row fails are discarded
+	parser, err = NewRowParser(schema, WithRequireDynamicValue())
+	assert.NoError(t, err)
+	err = parser.ParseColumnar([]any{
+		decodeRow(t, `{"id": 3, "vector": [0.5, 0.6], "name": "c", "tags": [3], "x": 9}`),
+		decodeRow(t, `{"id": 4, "vector": [0.7, 0.8], "name": "d", "tags": [4]}`),
+	}, columns)
+	assert.ErrorContains(t, err, "failed to parse row 1")
+	assert.Equal(t, 3, columns.NumRows)
+	assert.Equal(t, []int64{1, 2, 3}, columns.Int64[100])
+	assert.Equal(t, [][]float32{{0.1, 0.2}, {0.3, 0.4}, {0.5, 0.6}}, columns.FloatVector[101])
+	assert.Equal(t, []string{"a", "b", "c"}, columns.String[102])
+	assert.Equal(t, 3, len(columns.Array[103]))
+	assert.Equal(t, 3, len(columns.Bytes[104]))
+
+	// the computed fields are supported by building the row
+	parser, err = NewRowParser(newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "name", DataType: schemapb.DataType_VarChar}),
+		WithComputedField(102, func(row Row) (any, error) {
+			return fmt.Sprintf("n%d", row[100]), nil
+		}))
+	assert.NoError(t, err)
+	columns = NewColumnBuffers()
+	err = parser.ParseColumnar([]any{decodeRow(t, `{"id": 1, "vector": [0.1, 0.2]}`)}, columns)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1"}, columns.String[102])
+
+	parser, err = NewRowParser(newTestSchema(), WithDeleteMarker("_deleted"))
+	assert.NoError(t, err)
+	err = parser.ParseColumnar([]any{decodeRow(t, `{"id": 1, "_deleted": true}`)}, NewColumnBuffers())
+	assert.ErrorContains(t, err, "the deletion row cannot be appended to the columns")
+}
+
+func benchmarkColumnarRows(b *testing.B, parse func(parser RowParser, raws []any) error) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "name", DataType: schemapb.DataType_VarChar},
+		&schemapb.FieldSchema{FieldID: 103, Name: "count", DataType: schemapb.DataType_Int32},
+	)
+	parser, err := NewRowParser(schema, WithIgnoreKeys())
+	assert.NoError(b, err)
+	raws := make([]any, 0, 100)
+	for i := 0; i < 100; i++ {
+		raws = append(raws, decodeRow(&testing.T{}, fmt.Sprintf(`{"id": %d, "vector": [0.1, 0.2], "name": "n%d", "count": %d}`, i, i, i)))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := parse(parser, raws); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRowParser_ParseThenColumns(b *testing.B) {
+	benchmarkColumnarRows(b, func(parser RowParser, raws []any) error {
+		columns := NewColumnBuffers()
+		for _, raw := range raws {
+			row, err := parser.Parse(raw)
+			if err != nil {
+				return err
+			}
+			for fieldID, value := range row {
+				if err = columns.Append(fieldID, value); err != nil {
+					return err
+				}
+			}
+			if err = columns.EndRow(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func BenchmarkRowParser_ParseColumnar(b *testing.B) {
+	benchmarkColumnarRows(b, func(parser RowParser, raws []any) error {
+		return parser.ParseColumnar(raws, NewColumnBuffers())
+	})
+}
+
+func TestRowParser_WarnDoublePrecisionLoss(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "d", DataType: schemapb.DataType_Double})
+	warned := make([]string, 0)
+	parser, err := NewRowParser(schema, WithWarnDoublePrecisionLoss(func(fieldName string, value string, parsed float64) {
+		assert.Equal(t, "d", fieldName)
+		warned = append(warned, value)
+	}))
+	assert.NoError(t, err)
+
+	for _, value := range []string{"0.1", "1.0", "100", "1e2", "-3.25", "1.7976931348623157e308"} {
+		_, err = parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": 1, "vector": [0.1, 0.2], "d": %s}`, value)))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 0, len(warned))
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "d": 0.12345678901234567890123}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.12345678901234568, row[102])
+	assert.Equal(t, []string{"0.12345678901234567890123"}, warned)
+}
+
+func TestRowParser_IntegerFloatVector(t *testing.T) {
+	schema := newTestSchema()
+	warned := 0
+	warnFn := func(fieldName string) {
+		assert.Equal(t, "vector", fieldName)
+		warned++
+	}
+
+	parser, err := NewRowParser(schema, WithWarnIntegerFloatVector(warnFn))
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0, 1]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0, 1}, row[101])
+	assert.Equal(t, 1, warned)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0, 1.0]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, warned)
+
+	// designated fields are treated as floats without warning
+	core, logs := observer.New(zapcore.DebugLevel)
+	parser, err = NewRowParser(schema, WithWarnIntegerFloatVector(warnFn),
+		WithIntegerFloatVectorFields(101), WithLogger(zap.New(core)))
+	assert.NoError(t, err)
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0, 1]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0, 1}, row[101])
+	assert.Equal(t, 1, warned)
+	assert.Equal(t, 1, logs.FilterMessage("integer float vector treated as floats").Len())
+}
+
+func TestRowParser_AutoIDWithDynamicField(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true})
+	schema.Fields[0].AutoID = true
+	parser, err := NewRowParser(schema)
+	assert.NoError(t, err)
+
+	// the auto-generated primary key must not be kept as a dynamic key
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "x": 8}`))
+	assert.ErrorContains(t, err, "the primary key 'id' is auto-generated")
+
+	row, err := parser.Parse(decodeRow(t, `{"vector": [0.1, 0.2], "x": 8}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"x":8}`), row[102])
+}
+
+func TestRowParser_ArrayFromJSONString(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "arr", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_Int64})
+	parser, err := NewRowParser(schema, WithArrayFromJSONString())
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "arr": "[1, 2, 12345678901234567]"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 12345678901234567}, row[102].(*schemapb.ScalarField).GetLongData().GetData())
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "arr": [3]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3}, row[102].(*schemapb.ScalarField).GetLongData().GetData())
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "arr": "[1, 2"}`))
+	assert.ErrorContains(t, err, "failed to decode JSON string for array field 'arr'")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "arr": "{\"a\": 1}"}`))
+	assert.ErrorContains(t, err, "for array field 'arr' is not an array")
+
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "arr": "[1, 2]"}`))
+	assert.ErrorContains(t, err, "expected type 'Array'")
+}
+
+func TestRowParser_Dim(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{
+			FieldID:    102,
+			Name:       "bin",
+			DataType:   schemapb.DataType_BinaryVector,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "16"}},
+		},
+		&schemapb.FieldSchema{FieldID: 103, Name: "name", DataType: schemapb.DataType_VarChar},
+	)
+	parser, err := NewRowParser(schema)
+	assert.NoError(t, err)
+
+	dim, ok := parser.Dim("vector")
+	assert.True(t, ok)
+	assert.Equal(t, 2, dim)
+	dim, ok = parser.Dim("bin")
+	assert.True(t, ok)
+	assert.Equal(t, 16, dim)
+	_, ok = parser.Dim("name")
+	assert.False(t, ok)
+	_, ok = parser.Dim("unknown")
+	assert.False(t, ok)
+
+	// each vector field is checked against its own dim
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "bin": [1, 2], "name": "a"}`))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "bin": [1], "name": "a"}`))
+	assert.ErrorContains(t, err, "expected dim '16' for field 'bin'")
+}
+
+func TestRowParser_DecimalIntegers(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "zip", DataType: schemapb.DataType_Int32},
+		&schemapb.FieldSchema{FieldID: 103, Name: "arr", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_Int64},
+	)
+	// zero-padded numbers are not valid JSON literals, but may be produced by other decoders
+	raw := map[string]any{
+		"id":     json.Number("1"),
+		"vector": []any{json.Number("0.1"), json.Number("0.2")},
+		"zip":    json.Number("010"),
+		"arr":    []any{json.Number("010"), json.Number("7")},
+	}
+
+	parser, err := NewRowParser(schema)
+	assert.NoError(t, err)
+	row, err := parser.Parse(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(8), row[102])
+	assert.Equal(t, []int64{8, 7}, row[103].(*schemapb.ScalarField).GetLongData().GetData())
+
+	parser, err = NewRowParser(schema, WithDecimalIntegers())
+	assert.NoError(t, err)
+	row, err = parser.Parse(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10), row[102])
+	assert.Equal(t, []int64{10, 7}, row[103].(*schemapb.ScalarField).GetLongData().GetData())
+
+	raw["zip"] = json.Number("0x10")
+	_, err = parser.Parse(raw)
+	assert.Error(t, err)
+}
+
+func TestRowParser_VarCharHash(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{
+			FieldID:    102,
+			Name:       "email",
+			DataType:   schemapb.DataType_VarChar,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.MaxLengthKey, Value: "64"}},
+		},
+		&schemapb.FieldSchema{FieldID: 103, Name: "n", DataType: schemapb.DataType_Int64},
+	)
+	raw := `{"id": 1, "vector": [0.1, 0.2], "email": "a@b.c", "n": 1}`
+
+	parser, err := NewRowParser(schema, WithVarCharHash(102, HashSHA256))
+	assert.NoError(t, err)
+	row1, err := parser.Parse(decodeRow(t, raw))
+	assert.NoError(t, err)
+	row2, err := parser.Parse(decodeRow(t, raw))
+	assert.NoError(t, err)
+	assert.Equal(t, row1[102], row2[102])
+	assert.Equal(t, "d648b243a3e817eaa3309e00e183483f2867baadf522099f0c2121770536b25a", row1[102])
+
+	parser, err = NewRowParser(schema, WithVarCharHash(102, HashMD5))
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, raw))
+	assert.NoError(t, err)
+	assert.Equal(t, "5d60d4e28066df254d5452f92c910092", row[102])
+
+	// the sha512 digest exceeds max_length
+	parser, err = NewRowParser(schema, WithVarCharHash(102, HashSHA512))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, raw))
+	assert.ErrorContains(t, err, "exceeds max_length 64")
+
+	_, err = NewRowParser(schema, WithVarCharHash(102, "crc"))
+	assert.ErrorContains(t, err, "unsupported hash algorithm")
+	_, err = NewRowParser(schema, WithVarCharHash(103, HashMD5))
+	assert.ErrorContains(t, err, "only supported for VarChar field")
+}
+
+func TestRowParser_ComputedField(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "first", DataType: schemapb.DataType_VarChar},
+		&schemapb.FieldSchema{FieldID: 103, Name: "last", DataType: schemapb.DataType_VarChar},
+		&schemapb.FieldSchema{FieldID: 104, Name: "full", DataType: schemapb.DataType_VarChar},
+		&schemapb.FieldSchema{FieldID: 105, Name: "length", DataType: schemapb.DataType_Int32},
+	)
+	fullName := func(row Row) (any, error) {
+		return row[102].(string) + " " + row[103].(string), nil
+	}
+	length := func(row Row) (any, error) {
+		return json.Number(fmt.Sprint(len(row[104].(string)))), nil
+	}
+	parser, err := NewRowParser(schema, WithComputedField(104, fullName), WithComputedField(105, length))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "first": "John", "last": "Doe"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "John Doe", row[104])
+	assert.Equal(t, int32(8), row[105])
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "first": "John", "last": "Doe", "full": "x"}`))
+	assert.ErrorContains(t, err, "the field 'full' is computed")
+
+	// the computed value is validated
+	parser, err = NewRowParser(schema, WithComputedField(104, fullName), WithComputedField(105, func(row Row) (any, error) {
+		return "8", nil
+	}))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "first": "John", "last": "Doe"}`))
+	assert.ErrorContains(t, err, "expected type 'Int32' for field 'length'")
+
+	parser, err = NewRowParser(schema, WithComputedField(104, fullName), WithComputedField(105, func(row Row) (any, error) {
+		return nil, fmt.Errorf("mock error")
+	}))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "first": "John", "last": "Doe"}`))
+	assert.ErrorContains(t, err, "failed to compute field 'length'")
+
+	_, err = NewRowParser(schema, WithComputedField(999, fullName))
+	assert.ErrorContains(t, err, "computed field 999 is not found")
+}
+
+func TestRowParser_CanonicalDynamicField(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true})
+	raw1 := `{"id": 1, "vector": [0.1, 0.2], "b": {"y": 1.0, "x": [1e2, 0.50]}, "a": 12345678901234567}`
+	raw2 := `{"a": 12345678901234567, "id": 1, "b": {"x": [100, 0.5], "y": 1}, "vector": [0.1, 0.2]}`
+
+	parser, err := NewRowParser(schema, WithCanonicalDynamicField())
+	assert.NoError(t, err)
+	row1, err := parser.Parse(decodeRow(t, raw1))
+	assert.NoError(t, err)
+	row2, err := parser.Parse(decodeRow(t, raw2))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":12345678901234567,"b":{"x":[100,0.5],"y":1}}`, string(row1[102].([]byte)))
+	assert.Equal(t, row1[102], row2[102])
+
+	// numbers are kept verbatim by default
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	row1, err = parser.Parse(decodeRow(t, raw1))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":12345678901234567,"b":{"x":[1e2,0.50],"y":1.0}}`, string(row1[102].([]byte)))
+}
+
+func TestRowParser_DynamicKeyOrder(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true})
+	raw1 := `{"id": 1, "vector": [0.1, 0.2], "c": 3, "source": "web", "a": {"y": 1.0, "x": "<"}, "type": "doc"}`
+	raw2 := `{"type": "doc", "a": {"x": "<", "y": 1}, "id": 1, "source": "web", "vector": [0.1, 0.2], "c": 3.0}`
+
+	parser, err := NewRowParser(schema, WithDynamicKeyOrder("type", "missing", "source", "type"), WithCanonicalDynamicField())
+	assert.NoError(t, err)
+	row1, err := parser.Parse(decodeRow(t, raw1))
+	assert.NoError(t, err)
+	row2, err := parser.Parse(decodeRow(t, raw2))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"doc","source":"web","a":{"x":"\u003c","y":1},"c":3}`, string(row1[102].([]byte)))
+	assert.Equal(t, row1[102], row2[102])
+
+	row1, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(row1[102].([]byte)))
+}
+
+func TestRowParser_DynamicKeyPattern(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "name", DataType: schemapb.DataType_VarChar},
+		&schemapb.FieldSchema{FieldID: 103, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+	)
+	parser, err := NewRowParser(schema, WithDynamicKeyPattern("^attr_"))
+	assert.NoError(t, err)
+
+	// the schema fields are not checked against the pattern
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": "a", "attr_color": "red"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "a", row[102])
+	assert.Equal(t, `{"attr_color":"red"}`, string(row[103].([]byte)))
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": "a", "attr_color": "red", "colour": "red"}`))
+	assert.ErrorContains(t, err, "the field 'colour' is not defined in schema, nor matches the dynamic key pattern '^attr_'")
+	var parseErr *ParseError
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, ErrKindUnknownField, parseErr.Kind)
+	assert.Equal(t, "colour", parseErr.FieldName)
+
+	_, err = NewRowParser(schema, WithDynamicKeyPattern("(attr"))
+	assert.ErrorContains(t, err, "invalid dynamic key pattern '(attr'")
+	_, err = NewRowParser(newTestSchema(), WithDynamicKeyPattern("^attr_"))
+	assert.ErrorContains(t, err, "dynamic field is not enabled")
+}
+
+func TestRowParser_RequireDynamicValue(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true})
+	parser, err := NewRowParser(schema, WithRequireDynamicValue())
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "x": 1}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"x":1}`, string(row[102].([]byte)))
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2]}`))
+	assert.ErrorContains(t, err, "the row with primary key '1' has no value for dynamic field '$meta', "+
+		"at least one key not defined in schema is required")
+	var parseErr *ParseError
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, ErrKindMissingField, parseErr.Kind)
+	_, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2], "x": 1}`,
+		`{"id": 2, "vector": [0.1, 0.2]}`,
+	))
+	assert.ErrorContains(t, err, "failed to parse row 1")
+
+	// the row is accepted without the option
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{}`, string(row[102].([]byte)))
+
+	_, err = NewRowParser(newTestSchema(), WithRequireDynamicValue())
+	assert.ErrorContains(t, err, "dynamic field is not enabled")
+}
+
+func TestRowParser_ByteVectorMismatch(t *testing.T) {
+	for _, dt := range []schemapb.DataType{schemapb.DataType_BinaryVector, schemapb.DataType_Float16Vector} {
+		schema := newTestSchema()
+		schema.Fields[1].DataType = dt
+		schema.Fields[1].TypeParams[0].Value = "16"
+		parser, err := NewRowParser(schema)
+		assert.NoError(t, err)
+
+		_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.12345, 0.2, 0.3, 0.4]}`))
+		assert.ErrorContains(t, err, "element at index 0 looks like a float '0.12345', did you mean a FloatVector?")
+		_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [1, 2, 300, 4]}`))
+		assert.ErrorContains(t, err, "expects packed byte values in [0, 255]")
+		assert.ErrorContains(t, err, "element at index 2 is '300'")
+		// dim is checked after the values
+		_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [1]}`))
+		assert.ErrorContains(t, err, "expected dim '16'")
+	}
+}
+
+func TestRowParser_IgnoreKeys(t *testing.T) {
+	raw := `{"id": 1, "vector": [0.1, 0.2], "_source": "s3", "_ingested_at": 1700000000, "x": 8}`
+
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true})
+	parser, err := NewRowParser(schema, WithIgnoreKeys("_source", "_ingested_at"))
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, raw))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"x":8}`), row[102])
+
+	schema = newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "x", DataType: schemapb.DataType_Int64})
+	parser, err = NewRowParser(schema, WithIgnoreKeys("_source", "_ingested_at"))
+	assert.NoError(t, err)
+	row, err = parser.Parse(decodeRow(t, raw))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(row))
+
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, raw))
+	assert.ErrorContains(t, err, "is not defined in schema")
+}
+
+func TestRowParser_NarrowIntegerArray(t *testing.T) {
+	for _, c := range []struct {
+		eleType  schemapb.DataType
+		valid    string
+		expect   []int32
+		overflow string
+	}{
+		{schemapb.DataType_Int8, "[-128, 127]", []int32{-128, 127}, "[1, 200]"},
+		{schemapb.DataType_Int16, "[-32768, 32767]", []int32{-32768, 32767}, "[1, 40000]"},
+		{schemapb.DataType_Int32, "[-2147483648, 2147483647]", []int32{-2147483648, 2147483647}, "[1, 2147483648]"},
+	} {
+		schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "arr", DataType: schemapb.DataType_Array, ElementType: c.eleType})
+		parser, err := NewRowParser(schema)
+		assert.NoError(t, err)
+		row, err := parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": 1, "vector": [0.1, 0.2], "arr": %s}`, c.valid)))
+		assert.NoError(t, err)
+		assert.Equal(t, c.expect, row[102].(*schemapb.ScalarField).GetIntData().GetData())
+
+		_, err = parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": 1, "vector": [0.1, 0.2], "arr": %s}`, c.overflow)))
+		assert.ErrorContains(t, err, fmt.Sprintf("at index 1 is out of range for element type '%s' for field 'arr'", c.eleType.String()))
+		parseErr := &ParseError{}
+		assert.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, ErrKindInvalidValue, parseErr.Kind)
+		assert.Equal(t, "arr", parseErr.FieldName)
+	}
+}
+
+func TestRowParser_DynamicFieldMerge(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true})
+
+	parser, err := NewRowParser(schema, WithDynamicFieldMerge(DynamicMergeError))
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "$meta": {"a": 1, "n": {"x": 1}}, "b": 2, "n": {"y": 2}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1,"b":2,"n":{"x":1,"y":2}}`, string(row[102].([]byte)))
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "$meta": "{\"a\": 1}", "b": 2}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1,"b":2}`, string(row[102].([]byte)))
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "$meta": {"n": {"x": 1}}, "n": {"x": 2}}`))
+	assert.ErrorContains(t, err, "conflict dynamic key 'n.x'")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "$meta": [1]}`))
+	assert.ErrorContains(t, err, "should be a JSON object")
+
+	parser, err = NewRowParser(schema, WithDynamicFieldMerge(DynamicMergeLastWins))
+	assert.NoError(t, err)
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "$meta": {"n": {"x": 1, "z": 3}}, "n": {"x": 2}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"n":{"x":2,"z":3}}`, string(row[102].([]byte)))
+
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "$meta": {"a": 1}}`))
+	assert.ErrorContains(t, err, "explicit specification of '$meta' is not allowed")
+}
+
+func TestRowParser_MaxRowBytes(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "text", DataType: schemapb.DataType_VarChar})
+	// id: 8 bytes, vector: 8 bytes, text: 4 bytes
+	raw := `{"id": 1, "vector": [0.1, 0.2], "text": "abcd"}`
+
+	parser, err := NewRowParser(schema, WithMaxRowBytes(20))
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, raw))
+	assert.NoError(t, err)
+	assert.Equal(t, 20, EstimateRowSize(row))
+
+	parser, err = NewRowParser(schema, WithMaxRowBytes(19))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, raw))
+	assert.ErrorContains(t, err, "the estimated size 20 bytes of row with primary key '1' exceeds the limit 19 bytes")
+}
+
+func TestRowParser_MaxFieldsPerRow(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true})
+	parser, err := NewRowParser(schema, WithMaxFieldsPerRow(3))
+	assert.NoError(t, err)
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "a": 1}`))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "a": 1, "b": 2}`))
+	assert.ErrorContains(t, err, "the row with primary key '1' has 4 keys, exceeds the limit 3 keys per row")
+	_, err = parser.Parse(decodeRow(t, `{"vector": [0.1, 0.2], "a": 1, "b": 2, "c": 3}`))
+	assert.ErrorContains(t, err, "the row has 4 keys, exceeds the limit 3 keys per row")
+
+	var raw map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal([]byte(`{"id": 1, "vector": [0.1, 0.2], "a": 1, "b": 2}`), &raw))
+	_, err = parser.ParseRaw(raw)
+	assert.ErrorContains(t, err, "the row with primary key '1' has 4 keys")
+
+	// the row index is reported by the batch
+	_, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [0.1, 0.2]}`,
+		`{"id": 2, "vector": [0.1, 0.2], "a": 1, "b": 2}`,
+	))
+	assert.ErrorContains(t, err, "failed to parse row 1")
+}
+
+func TestRowParser_UnwrapSingletonVectorArray(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema(), WithUnwrapSingletonVectorArray())
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [[0.1, 0.2]]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.1, 0.2}, row[101])
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.1, 0.2}, row[101])
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [[0.1], [0.2]]}`))
+	assert.ErrorContains(t, err, "expected type 'FloatVector'")
+
+	// the batch and the equal dim checks read the unwrapped vector too
+	schema := newTestSchema(&schemapb.FieldSchema{
+		FieldID:    102,
+		Name:       "vector2",
+		DataType:   schemapb.DataType_FloatVector,
+		TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}},
+	})
+	parser, err = NewRowParser(schema, WithUnwrapSingletonVectorArray(), WithBatchDimCheck(), WithEqualDim(101, 102))
+	assert.NoError(t, err)
+	raws := decodeRows(t,
+		`{"id": 1, "vector": [[0.1, 0.2]], "vector2": [0.3, 0.4]}`,
+		`{"id": 2, "vector": [[0.5, 0.6]], "vector2": [[0.7, 0.8]]}`,
+	)
+	rows, err := parser.ParseBatch(raws)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+	rows, _, err = parser.ParseBatchWithResult(raws)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+	_, err = parser.ParseBatch(decodeRows(t,
+		`{"id": 1, "vector": [[0.1, 0.2]], "vector2": [[0.3, 0.4]]}`,
+		`{"id": 2, "vector": [[0.5, 0.6]], "vector2": [[0.7, 0.8, 0.9]]}`,
+	))
+	assert.ErrorContains(t, err, "failed to parse row 1")
+	assert.ErrorContains(t, err, "got vector=2, vector2=3")
+
+	parser, err = NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [[0.1, 0.2]]}`))
+	assert.Error(t, err)
+}
+
+func TestRowParser_Aliases(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{
+		FieldID:  102,
+		Name:     "name",
+		DataType: schemapb.DataType_VarChar,
+		TypeParams: []*commonpb.KeyValuePair{
+			{Key: common.MaxLengthKey, Value: "16"},
+		},
+	})
+	parser, err := NewRowParser(schema, WithAliases(map[string][]string{
+		"name":   {"title", "label"},
+		"vector": {"embedding"},
+	}))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "embedding": [0.1, 0.2], "title": "a"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "a", row[102])
+	assert.Equal(t, []float32{0.1, 0.2}, row[101])
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "title": "a", "label": "b"}`))
+	assert.ErrorContains(t, err, "the field 'name' is provided more than once by aliases")
+
+	_, err = NewRowParser(schema, WithAliases(map[string][]string{"name": {"vector"}}))
+	assert.ErrorContains(t, err, "alias 'vector' of field 'name' collides with another field name")
+
+	_, err = NewRowParser(schema, WithAliases(map[string][]string{"name": {"x"}, "vector": {"x"}}))
+	assert.ErrorContains(t, err, "alias 'x' is used by both field")
+
+	_, err = NewRowParser(schema, WithAliases(map[string][]string{"unknown": {"x"}}))
+	assert.ErrorContains(t, err, "the aliased field 'unknown' is not defined in schema")
+}
+
+func TestRowParser_HasDynamicField(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	_, ok := parser.HasDynamicField()
+	assert.False(t, ok)
+
+	parser, err = NewRowParser(newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+	))
+	assert.NoError(t, err)
+	name, ok := parser.HasDynamicField()
+	assert.True(t, ok)
+	assert.Equal(t, "$meta", name)
+}
+
+func TestRowParser_AcceptIntegerForFloat(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "f", DataType: schemapb.DataType_Float},
+		&schemapb.FieldSchema{FieldID: 103, Name: "d", DataType: schemapb.DataType_Double},
+	)
+	counter := atomic.NewInt64(0)
+	parser, err := NewRowParser(schema, WithIntegerForFloatCounter(counter))
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [1, 2], "f": 3, "d": 4.0}`))
+	assert.NoError(t, err)
+	assert.Equal(t, float32(3), row[102])
+	assert.Equal(t, float64(4), row[103])
+	// integer elements of float vectors are not counted
+	assert.Equal(t, int64(1), counter.Load())
+
+	parser, err = NewRowParser(schema, WithAcceptIntegerForFloat(false))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [1, 2], "f": 3.0, "d": 4e0}`))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [1, 2], "f": 3.0, "d": 4}`))
+	assert.ErrorContains(t, err, "field 'd' of type 'Double' expects a float-formatted value, got integer '4'")
+}
+
+func TestRowParser_VectorComponentKeys(t *testing.T) {
+	schema := newTestSchema()
+	parser, err := NewRowParser(schema, WithVectorComponentKeys(101, "y", "x"))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": {"x": 0.1, "y": 0.2}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.2, 0.1}, row[101])
+
+	// plain arrays are still accepted
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.1, 0.2}, row[101])
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": {"x": 0.1}}`))
+	assert.ErrorContains(t, err, "component key 'y' of field 'vector' is missed")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": {"x": 0.1, "y": 0.2, "z": 0.3}}`))
+	assert.ErrorContains(t, err, "unexpected component key 'z' for field 'vector'")
+
+	_, err = NewRowParser(schema, WithVectorComponentKeys(101, "x", "y", "z"))
+	assert.ErrorContains(t, err, "field 'vector' expects 2 distinct component keys")
+	_, err = NewRowParser(schema, WithVectorComponentKeys(101, "x", "x"))
+	assert.ErrorContains(t, err, "field 'vector' expects 2 distinct component keys")
+	_, err = NewRowParser(schema, WithVectorComponentKeys(100, "x", "y"))
+	assert.ErrorContains(t, err, "component keys are only supported for FloatVector field")
+}
+
+func TestRowParser_DedupArrayElements(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{
+			FieldID:     102,
+			Name:        "tags",
+			DataType:    schemapb.DataType_Array,
+			ElementType: schemapb.DataType_VarChar,
+			TypeParams:  []*commonpb.KeyValuePair{{Key: common.MaxLengthKey, Value: "16"}},
+		},
+		&schemapb.FieldSchema{FieldID: 103, Name: "nums", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_Int64},
+		&schemapb.FieldSchema{FieldID: 104, Name: "raw", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_Int64},
+	)
+	parser, err := NewRowParser(schema, WithDedupArrayElements(102, 103))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "tags": ["b", "a", "b", "c", "a"], "nums": [3, 1, 3], "raw": [3, 1, 3]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "a", "c"}, row[102].(*schemapb.ScalarField).GetStringData().GetData())
+	assert.Equal(t, []int64{3, 1}, row[103].(*schemapb.ScalarField).GetLongData().GetData())
+	assert.Equal(t, []int64{3, 1, 3}, row[104].(*schemapb.ScalarField).GetLongData().GetData())
+
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "tags": ["c", "b", "a"], "nums": [], "raw": []}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c", "b", "a"}, row[102].(*schemapb.ScalarField).GetStringData().GetData())
+	assert.Equal(t, 0, len(row[103].(*schemapb.ScalarField).GetLongData().GetData()))
+
+	_, err = NewRowParser(schema, WithDedupArrayElements(101))
+	assert.ErrorContains(t, err, "dedup is only supported for Array field")
+}
+
+func TestRowParser_ASCIIOnly(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "code", DataType: schemapb.DataType_VarChar},
+		&schemapb.FieldSchema{FieldID: 103, Name: "name", DataType: schemapb.DataType_VarChar},
+	)
+	parser, err := NewRowParser(schema, WithASCIIOnly(102))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "code": "abc~", "name": "café"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "abc~", row[102])
+	assert.Equal(t, "café", row[103])
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "code": "café", "name": "a"}`))
+	assert.ErrorContains(t, err, "value of field 'code' must be ASCII only, got non-ASCII byte 0xc3 at offset 3")
+}
+
+func TestRowParser_BoolStrings(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "b", DataType: schemapb.DataType_Bool},
+		&schemapb.FieldSchema{FieldID: 103, Name: "arr", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_Bool},
+	)
+	parser, err := NewRowParser(schema, WithBoolStrings([]string{"Y", "yes", "t"}, []string{"N", "no", "f"}))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "b": "y", "arr": ["YES", "No", true, "f"]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, true, row[102])
+	assert.Equal(t, []bool{true, false, true, false}, row[103].(*schemapb.ScalarField).GetBoolData().GetData())
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "b": "maybe", "arr": []}`))
+	assert.ErrorContains(t, err, "expected type 'Bool' for field 'b'")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "b": "N", "arr": ["Y", "x"]}`))
+	assert.ErrorContains(t, err, "expected element type 'Bool' in array field")
+
+	// strings are rejected by default
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "b": "Y", "arr": []}`))
+	assert.ErrorContains(t, err, "expected type 'Bool' for field 'b'")
+
+	_, err = NewRowParser(schema, WithBoolStrings([]string{"y"}, []string{"Y"}))
+	assert.ErrorContains(t, err, "the string 'y' cannot be both truthy and falsy")
+}
+
+func TestRowParser_CanonicalJSONFields(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "j", DataType: schemapb.DataType_JSON})
+	parser, err := NewRowParser(schema, WithCanonicalJSONFields())
+	assert.NoError(t, err)
+
+	for _, raw := range []string{
+		`{"id": 1, "vector": [0.1, 0.2], "j": "{\"b\": 2.0, \"a\": [1e0, 0.50]}"}`,
+		`{"id": 1, "vector": [0.1, 0.2], "j": {"a": [1, 0.5], "b": 2}}`,
+	} {
+		row, err := parser.Parse(decodeRow(t, raw))
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a":[1,0.5],"b":2}`, string(row[102].([]byte)))
+	}
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "j": "{\"a\": 1} x"}`))
+	assert.Error(t, err)
+
+	// the bytes are verbatim by default
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "j": "{\"b\": 2.0, \"a\": 1}"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"b": 2.0, "a": 1}`, string(row[102].([]byte)))
+}
+
+func TestRowParser_NativeJSONValues(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "j", DataType: schemapb.DataType_JSON},
+		&schemapb.FieldSchema{FieldID: 103, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+	)
+	parser, err := NewRowParser(schema)
+	assert.NoError(t, err)
+
+	// the values produced by BSON or YAML decoders
+	native := func(j any) map[string]any {
+		return map[string]any{
+			"id":     int64(1),
+			"vector": []any{0.1, 0.2},
+			"j":      j,
+			"x":      map[any]any{"y": int64(math.MaxInt64)},
+		}
+	}
+	row, err := parser.Parse(native(map[any]any{
+		"big":    int64(math.MaxInt64),
+		"f":      0.1,
+		"nested": map[any]any{"a": int32(1)},
+		"list":   []any{float32(0.5), map[any]any{"b": true}},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"big":9223372036854775807,"f":0.1,"list":[0.5,{"b":true}],"nested":{"a":1}}`, string(row[102].([]byte)))
+	assert.Equal(t, `{"x":{"y":9223372036854775807}}`, string(row[103].([]byte)))
+
+	_, err = parser.Parse(native(map[string]any{"a": []any{1.0, math.NaN()}}))
+	assert.ErrorContains(t, err, "the value 'NaN' at '$.a[1]' is not representable in JSON")
+	_, err = parser.Parse(native(map[any]any{"a": map[any]any{1: "b"}}))
+	assert.ErrorContains(t, err, "the key '1' at '$.a' is not a string")
+	_, err = parser.Parse(native([]any{1}))
+	assert.Error(t, err)
+}
+
+func TestRowParser_DecimalSeparator(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "f", DataType: schemapb.DataType_Float},
+		&schemapb.FieldSchema{FieldID: 103, Name: "d", DataType: schemapb.DataType_Double},
+	)
+	parser, err := NewRowParser(schema, WithDecimalSeparator(','))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "f": "3,5", "d": "-0,25"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, float32(3.5), row[102])
+	assert.Equal(t, -0.25, row[103])
+
+	// numbers and strings without separator are accepted
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "f": 3.5, "d": "2"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, float32(3.5), row[102])
+	assert.Equal(t, float64(2), row[103])
+
+	for _, value := range []string{"1,234,5", "1.234,5", "3.14"} {
+		_, err = parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": 1, "vector": [0.1, 0.2], "f": 1, "d": "%s"}`, value)))
+		assert.ErrorContains(t, err, fmt.Sprintf("ambiguous number '%s' for field 'd'", value))
+	}
+	for _, value := range []string{"abc", "NaN", "1,", ",5", ""} {
+		_, err = parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": 1, "vector": [0.1, 0.2], "f": 1, "d": "%s"}`, value)))
+		assert.ErrorContains(t, err, fmt.Sprintf("invalid number '%s' for field 'd'", value))
+	}
+
+	// strings are rejected by default
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "f": "3.5", "d": 1}`))
+	assert.ErrorContains(t, err, "expected type 'Float' for field 'f'")
+
+	_, err = NewRowParser(schema, WithDecimalSeparator('1'))
+	assert.ErrorContains(t, err, "invalid decimal separator '1'")
+}
+
+func TestRowParser_ProjectFields(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "name", DataType: schemapb.DataType_VarChar, IsPartitionKey: true},
+		&schemapb.FieldSchema{FieldID: 103, Name: "age", DataType: schemapb.DataType_Int64},
+	)
+	parser, err := NewRowParser(schema, WithProjectFields(100, 101))
+	assert.NoError(t, err)
+
+	// unlisted fields are neither required nor parsed
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(row))
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": 1, "age": "x"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(row))
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1}`))
+	assert.ErrorContains(t, err, "value of field 'vector' is missed")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "other": 1}`))
+	assert.ErrorContains(t, err, "the field 'other' is not defined in schema")
+	_, ok := parser.FieldType("age")
+	assert.False(t, ok)
+
+	_, err = NewRowParser(schema, WithProjectFields(100, 999))
+	assert.ErrorContains(t, err, "projected field 999 is not found in schema")
+}
+
+func TestRowParser_NumericRange(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "age", DataType: schemapb.DataType_Int8},
+		&schemapb.FieldSchema{FieldID: 103, Name: "score", DataType: schemapb.DataType_Double},
+	)
+	parser, err := NewRowParser(schema, WithNumericRange(102, 0, 150), WithNumericRange(103, -1, 1))
+	assert.NoError(t, err)
+
+	for _, raw := range []string{
+		`{"id": 1, "vector": [0.1, 0.2], "age": 0, "score": -1}`,
+		`{"id": 1, "vector": [0.1, 0.2], "age": 127, "score": 1.0}`,
+	} {
+		_, err = parser.Parse(decodeRow(t, raw))
+		assert.NoError(t, err)
+	}
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "age": -1, "score": 0}`))
+	assert.ErrorContains(t, err, "value -1 of field 'age' is out of range [0, 150]")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "age": 1, "score": 1.0001}`))
+	assert.ErrorContains(t, err, "value 1.0001 of field 'score' is out of range [-1, 1]")
+
+	_, err = NewRowParser(schema, WithNumericRange(101, 0, 1))
+	assert.ErrorContains(t, err, "range is only supported for numeric field")
+	_, err = NewRowParser(schema, WithNumericRange(102, 1, 0))
+	assert.ErrorContains(t, err, "invalid range [1, 0] of field 'age'")
+}
+
+func TestRowParser_ComponentRange(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema(), WithComponentRange(101, -1, 1))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [-1, 1]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{-1, 1}, row[101])
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.5, 1.5]}`))
+	assert.ErrorContains(t, err, "component 1.5 at index 1 of field 'vector' is out of range [-1, 1]")
+	var parseErr *ParseError
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, ErrKindInvalidValue, parseErr.Kind)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [-3, 0]}`))
+	assert.ErrorContains(t, err, "component -3 at index 0")
+
+	_, err = NewRowParser(newTestSchema(), WithComponentRange(100, -1, 1))
+	assert.ErrorContains(t, err, "component range is only supported for FloatVector field")
+	_, err = NewRowParser(newTestSchema(), WithComponentRange(101, 1, -1))
+	assert.ErrorContains(t, err, "invalid component range [1, -1] of field 'vector'")
+}
+
+func TestRowParser_ClampIntegers(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "i8", DataType: schemapb.DataType_Int8},
+		&schemapb.FieldSchema{FieldID: 103, Name: "i32", DataType: schemapb.DataType_Int32},
+	)
+	clamps := make([]string, 0)
+	parser, err := NewRowParser(schema, WithClampIntegers(func(fieldName string, value string, clamped int64) {
+		clamps = append(clamps, fmt.Sprintf("%s:%s=>%d", fieldName, value, clamped))
+	}, 102, 103))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "i8": 300, "i32": -3000000000}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int8(127), row[102])
+	assert.Equal(t, int32(math.MinInt32), row[103])
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "i8": -129, "i32": 5}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int8(-128), row[102])
+	assert.Equal(t, int32(5), row[103])
+	assert.ElementsMatch(t, []string{"i8:300=>127", "i32:-3000000000=>-2147483648", "i8:-129=>-128"}, clamps)
+
+	// the malformed values are not clamped
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "i8": 1.5, "i32": 5}`))
+	assert.Error(t, err)
+
+	// out-of-range values fail by default
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "i8": 300, "i32": 5}`))
+	assert.ErrorContains(t, err, "out of range")
+
+	_, err = NewRowParser(schema, WithClampIntegers(nil, 100))
+	assert.ErrorContains(t, err, "clamp is only supported for Int8, Int16 and Int32 field")
+}
+
+func TestRowParser_IndexKeyedVectors(t *testing.T) {
+	schema := newTestSchema()
+	schema.Fields[1].TypeParams[0].Value = "3"
+	parser, err := NewRowParser(schema, WithIndexKeyedVectors(101), WithVectorSentinels(SentinelFillZero, "NaN"))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": {"2": 0.3, "0": 0.1, "1": 0.2}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.1, 0.2, 0.3}, row[101])
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": {"0": "NaN", "1": null, "2": 0.3}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0, 0, 0.3}, row[101])
+
+	// completeness and finite checks still apply after filling
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": {"0": "NaN", "2": 0.3}}`))
+	assert.ErrorContains(t, err, "component 1 of field 'vector' is missed")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": {"0": null, "1": 1e39, "2": 0.3}}`))
+	assert.Error(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": {"0": 0.1, "1": 0.2, "3": 0.3}}`))
+	assert.ErrorContains(t, err, "invalid component index '3' for field 'vector' with dim 3")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": {"0": 0.1, "1": "inf", "2": 0.3}}`))
+	assert.ErrorContains(t, err, "expected type 'FloatVector' for field 'vector'")
+
+	parser, err = NewRowParser(schema, WithIndexKeyedVectors(101), WithVectorSentinels(SentinelReject, "NaN"))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": {"0": "NaN", "1": 0.2, "2": 0.3}}`))
+	assert.ErrorContains(t, err, "component 0 of field 'vector' is 'NaN', which is not allowed")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": {"0": 0.1, "1": null, "2": 0.3}}`))
+	assert.ErrorContains(t, err, "component 1 of field 'vector' is null, which is not allowed")
+
+	_, err = NewRowParser(schema, WithIndexKeyedVectors(100))
+	assert.ErrorContains(t, err, "index-keyed vector is only supported for FloatVector field")
+	_, err = NewRowParser(schema, WithIndexKeyedVectors(101), WithVectorComponentKeys(101, "x", "y", "z"))
+	assert.ErrorContains(t, err, "field 'vector' cannot have both component keys and index keys")
+}
+
+func TestRowParser_AllowedValues(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "status", DataType: schemapb.DataType_VarChar},
+		&schemapb.FieldSchema{FieldID: 103, Name: "level", DataType: schemapb.DataType_Int8},
+	)
+	parser, err := NewRowParser(schema, WithAllowedValues(102, "active", "inactive"), WithAllowedValues(103, 1, int64(2)))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "status": "inactive", "level": 2}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "inactive", row[102])
+	assert.Equal(t, int8(2), row[103])
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "status": "deleted", "level": 1}`))
+	assert.ErrorContains(t, err, "value 'deleted' of field 'status' is not one of the allowed values [active inactive]")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "status": "active", "level": 3}`))
+	assert.ErrorContains(t, err, "value '3' of field 'level' is not one of the allowed values [1 2]")
+	var parseErr *ParseError
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, ErrKindInvalidValue, parseErr.Kind)
+
+	_, err = NewRowParser(schema, WithAllowedValues(103, "1"))
+	assert.ErrorContains(t, err, "invalid allowed value '1' of Int8 field 'level'")
+	_, err = NewRowParser(schema, WithAllowedValues(101, 1))
+	assert.ErrorContains(t, err, "allowed values are only supported for VarChar and integer field")
+}
+
+func TestRowParser_FieldPipeline(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "name", DataType: schemapb.DataType_VarChar})
+	trim := func(value any) (any, error) {
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expects a string, got %v", value)
+		}
+		return strings.TrimSpace(str), nil
+	}
+	lower := func(value any) (any, error) {
+		return strings.ToLower(value.(string)), nil
+	}
+	nonEmpty := func(value any) (any, error) {
+		if value == "" {
+			return nil, fmt.Errorf("empty name")
+		}
+		return value, nil
+	}
+	parser, err := NewRowParser(schema,
+		RegisterFieldPipeline(102, []func(any) (any, error){trim, lower}),
+		RegisterFieldPipeline(102, []func(any) (any, error){nonEmpty}))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": "  Alice "}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", row[102])
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": 1}`))
+	assert.ErrorContains(t, err, "stage 0 of the pipeline of field 'name' failed, error: expects a string, got 1")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": "   "}`))
+	assert.ErrorContains(t, err, "stage 2 of the pipeline of field 'name' failed, error: empty name")
+
+	// the pipeline applies to the converter of the field as well
+	convert, err := parser.FieldConverter(102)
+	assert.NoError(t, err)
+	value, err := convert(" Bob")
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", value)
+
+	_, err = NewRowParser(schema, RegisterFieldPipeline(200, []func(any) (any, error){trim}))
+	assert.ErrorContains(t, err, "field 200 of the pipeline is not found")
+}
+
+func TestRowParser_JSONKeyAllowlist(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "j", DataType: schemapb.DataType_JSON})
+	parser, err := NewRowParser(schema, WithJSONKeyAllowlist(102, "b", "a"))
+	assert.NoError(t, err)
+
+	for _, raw := range []string{
+		`{"id": 1, "vector": [0.1, 0.2], "j": {"a": 1, "b": {"nested": 2}}}`,
+		`{"id": 1, "vector": [0.1, 0.2], "j": "{\"a\": 1}"}`,
+		`{"id": 1, "vector": [0.1, 0.2], "j": {}}`,
+	} {
+		_, err = parser.Parse(decodeRow(t, raw))
+		assert.NoError(t, err)
+	}
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "j": {"a": 1, "c": 2}}`))
+	assert.ErrorContains(t, err, "unexpected key 'c' in JSON field 'j', allowed keys: [a b]")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "j": "{\"c\": 1}"}`))
+	assert.ErrorContains(t, err, "unexpected key 'c' in JSON field 'j'")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "j": "[1, 2]"}`))
+	assert.ErrorContains(t, err, "field 'j' expects a JSON object")
+
+	_, err = NewRowParser(schema, WithJSONKeyAllowlist(101, "a"))
+	assert.ErrorContains(t, err, "key allowlist is only supported for JSON field")
+}
+
+func TestRowParser_MaxArrayElements(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "arr", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_Int64})
+	parser, err := NewRowParser(schema, WithMaxArrayElements(2, false))
+	assert.NoError(t, err)
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "arr": [1, 2]}`))
+	assert.NoError(t, err)
+	// the count is checked before the elements are converted
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "arr": [1, 2, "x"]}`))
+	assert.ErrorContains(t, err, "the array of field 'arr' has 3 elements, exceeds the limit 2")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2, 0.3], "arr": []}`))
+	assert.ErrorContains(t, err, "expected dim '2' for field 'vector'")
+
+	parser, err = NewRowParser(schema, WithMaxArrayElements(2, true))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2, 0.3], "arr": []}`))
+	assert.ErrorContains(t, err, "the array of field 'vector' has 3 elements, exceeds the limit 2")
+}
+
+func TestRowParser_EqualDim(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{
+			FieldID:    102,
+			Name:       "vector2",
+			DataType:   schemapb.DataType_FloatVector,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}},
+		},
+		&schemapb.FieldSchema{
+			FieldID:    103,
+			Name:       "bin",
+			DataType:   schemapb.DataType_BinaryVector,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "8"}},
+		},
+	)
+	parser, err := NewRowParser(schema, WithEqualDim(101, 102))
+	assert.NoError(t, err)
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "vector2": [0.3, 0.4], "bin": [1]}`))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "vector2": [0.3, 0.4, 0.5], "bin": [1]}`))
+	assert.ErrorContains(t, err, "the vectors of the row are required to have equal dim, got vector=2, vector2=3")
+	parseErr := &ParseError{}
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, ErrKindDimMismatch, parseErr.Kind)
+	// the dims equal to each other are still checked against the schema
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1], "vector2": [0.3], "bin": [1]}`))
+	assert.ErrorContains(t, err, "expected dim '2'")
+
+	// the dims are observed as the parser reads the vectors
+	parser, err = NewRowParser(schema, WithEqualDim(101, 102), WithFloatVectorFromBytes(binary.LittleEndian))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0, 0, 0, 0, 0, 0, 0, 0], "vector2": [0.3, 0.4], "bin": [1]}`))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0, 0, 0, 0, 0, 0, 0, 0], "vector2": [0.3, 0.4, 0.5], "bin": [1]}`))
+	assert.ErrorContains(t, err, "got vector=2, vector2=3")
+
+	// not checked by default
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "vector2": [0.3, 0.4, 0.5], "bin": [1]}`))
+	assert.NotContains(t, err.Error(), "required to have equal dim")
+
+	_, err = NewRowParser(schema, WithEqualDim(101, 103))
+	assert.ErrorContains(t, err, "the vector fields 'vector' and 'bin' are required to have equal dim, but the schema declares 2 and 8")
+	_, err = NewRowParser(schema, WithEqualDim(101, 100))
+	assert.ErrorContains(t, err, "equal dim is only supported for vector field")
+}
+
+func TestRowParser_IndexConstraint(t *testing.T) {
+	schema := newTestSchema()
+	_, err := NewRowParser(schema, WithIndexConstraint(101, IndexConstraint{IndexType: "DISKANN", MinDim: 1, MaxDim: 2}))
+	assert.NoError(t, err)
+	_, err = NewRowParser(schema, WithIndexConstraint(101, IndexConstraint{IndexType: "IVF_PQ", MinDim: 0, MaxDim: 0}))
+	assert.NoError(t, err)
+
+	_, err = NewRowParser(schema, WithIndexConstraint(101, IndexConstraint{IndexType: "DISKANN", MinDim: 32, MaxDim: 32768}))
+	assert.ErrorContains(t, err, "dim 2 of field 'vector' is not supported by index 'DISKANN', which requires dim in [32, 32768]")
+	_, err = NewRowParser(schema, WithIndexConstraint(101, IndexConstraint{IndexType: "HNSW", MaxDim: 1}))
+	assert.ErrorContains(t, err, "not supported by index 'HNSW'")
+	_, err = NewRowParser(schema, WithIndexConstraint(100, IndexConstraint{IndexType: "HNSW"}))
+	assert.ErrorContains(t, err, "index constraint is only supported for vector field")
+}
+
+func TestRowParser_ReservedMetaKey(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "$meta": {"a": 1}}`))
+	assert.ErrorContains(t, err, "the key '$meta' is reserved for the dynamic field, but dynamic field is not enabled on this collection")
+
+	// a field named $meta is matched as usual
+	parser, err = NewRowParser(newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON}))
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "$meta": {"a": 1}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(row[102].([]byte)))
+}
+
+func TestRowParser_ParseRaw(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "big", DataType: schemapb.DataType_Int64},
+		&schemapb.FieldSchema{FieldID: 103, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+	)
+	parser, err := NewRowParser(schema, WithIgnoreKeys("_ignored"))
+	assert.NoError(t, err)
+
+	raw := make(map[string]json.RawMessage)
+	assert.NoError(t, json.Unmarshal([]byte(`{"id": 1, "vector": [0.1, 0.2], "big": 9007199254740993, "x": 1.50}`), &raw))
+	row, err := parser.ParseRaw(raw)
+	assert.NoError(t, err)
+	// numbers are decoded as json.Number, so they are not rounded by float64
+	assert.Equal(t, int64(9007199254740993), row[102])
+	assert.Equal(t, `{"x":1.50}`, string(row[103].([]byte)))
+
+	// the values of ignored and unprojected keys are never decoded
+	raw["_ignored"] = json.RawMessage(`{invalid`)
+	_, err = parser.ParseRaw(raw)
+	assert.NoError(t, err)
+	parser, err = NewRowParser(schema, WithProjectFields(100, 101))
+	assert.NoError(t, err)
+	delete(raw, "_ignored")
+	raw["big"] = json.RawMessage(`{invalid`)
+	row, err = parser.ParseRaw(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), row[100])
+
+	raw["vector"] = json.RawMessage(`[0.1,`)
+	_, err = parser.ParseRaw(raw)
+	assert.ErrorContains(t, err, "failed to decode the value of key 'vector'")
+}
+
+func TestRowParser_ParseWithDynamicKeys(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true})
+	parser, err := NewRowParser(schema, WithDynamicFieldMerge(DynamicMergeError))
+	assert.NoError(t, err)
+
+	row, keys, err := parser.ParseWithDynamicKeys(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "b": 1, "a": {"x": 2}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, keys)
+	assert.Equal(t, `{"a":{"x":2},"b":1}`, string(row[102].([]byte)))
+
+	// the keys of the provided dynamic field value are included
+	_, keys, err = parser.ParseWithDynamicKeys(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "$meta": {"c": 1}, "b": 1}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, keys)
+
+	_, keys, err = parser.ParseWithDynamicKeys(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, keys)
+
+	_, _, err = parser.ParseWithDynamicKeys(decodeRow(t, `{"id": 1}`))
+	assert.Error(t, err)
+}
+
+func TestRowParser_NegativeByteValue(t *testing.T) {
+	for _, dt := range []schemapb.DataType{schemapb.DataType_BinaryVector, schemapb.DataType_Float16Vector} {
+		schema := newTestSchema()
+		schema.Fields[1].DataType = dt
+		schema.Fields[1].TypeParams[0].Value = "16"
+		parser, err := NewRowParser(schema)
+		assert.NoError(t, err)
+
+		for _, value := range []string{"-1", "-128"} {
+			_, err = parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": 1, "vector": [1, %s, 3, 4]}`, value)))
+			assert.ErrorContains(t, err, fmt.Sprintf("field 'vector' byte at index 1 must be 0-255; got negative value %s", value))
+		}
+		_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [1, 256, 3, 4]}`))
+		assert.ErrorContains(t, err, "element at index 1 is '256'")
+	}
+}
+
+func TestRowParser_ArrayOfJSON(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "arr", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_JSON})
+	_, err := NewRowParser(schema)
+	assert.ErrorContains(t, err, "Array of JSON is not supported, field 'arr' has element type JSON")
+}
+
+func TestRowParser_StrictIntegers(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "strict", DataType: schemapb.DataType_Int32},
+		&schemapb.FieldSchema{FieldID: 103, Name: "lenient", DataType: schemapb.DataType_Int64},
+	)
+	parser, err := NewRowParser(schema, WithIntegralDecimals(), WithStrictIntegers(102))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1.00, "vector": [0.1, 0.2], "strict": 1, "lenient": 2.0}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), row[100])
+	assert.Equal(t, int32(1), row[102])
+	assert.Equal(t, int64(2), row[103])
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "strict": 1.0, "lenient": 2}`))
+	assert.ErrorContains(t, err, "field 'strict' requires an integer, got '1.0' written with a decimal point")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "strict": 1, "lenient": 2.5}`))
+	assert.Error(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "strict": 1, "lenient": 2.0e1}`))
+	assert.Error(t, err)
+
+	// decimals are rejected by default
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "strict": 1, "lenient": 2.0}`))
+	assert.Error(t, err)
+}
+
+func TestRowParser_StripBOM(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "name", DataType: schemapb.DataType_VarChar})
+	parser, err := NewRowParser(schema, WithStripBOM(), WithASCIIOnly(102))
+	assert.NoError(t, err)
+
+	for _, c := range []struct {
+		raw    string
+		expect string
+	}{
+		{`{"id": 1, "vector": [0.1, 0.2], "name": "\ufeffabc"}`, "abc"},
+		{`{"id": 1, "vector": [0.1, 0.2], "name": "abc"}`, "abc"},
+		{`{"id": 1, "vector": [0.1, 0.2], "name": "\ufeff"}`, ""},
+	} {
+		row, err := parser.Parse(decodeRow(t, c.raw))
+		assert.NoError(t, err)
+		assert.Equal(t, c.expect, row[102])
+	}
+	// only a leading BOM is stripped
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": "a\ufeff"}`))
+	assert.ErrorContains(t, err, "non-ASCII byte 0xef at offset 1")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": "ï»¿a"}`))
+	assert.ErrorContains(t, err, "non-ASCII byte 0xc3 at offset 0")
+
+	// the BOM is kept by default
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": "\ufeffabc"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "\uFEFFabc", row[102])
+}
+
+func TestRowParser_FieldConverter(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "age", DataType: schemapb.DataType_Int32},
+		&schemapb.FieldSchema{FieldID: 103, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+	)
+	parser, err := NewRowParser(schema, WithNumericRange(102, 0, 150), WithBoolAsInteger())
+	assert.NoError(t, err)
+
+	convert, err := parser.FieldConverter(102)
+	assert.NoError(t, err)
+	column := []any{json.Number("1"), true, json.Number("150")}
+	values := make([]any, 0, len(column))
+	for _, value := range column {
+		data, err := convert(value)
+		assert.NoError(t, err)
+		values = append(values, data)
+	}
+	assert.Equal(t, []any{int32(1), int32(1), int32(150)}, values)
+	_, err = convert(json.Number("151"))
+	assert.ErrorContains(t, err, "value 151 of field 'age' is out of range [0, 150]")
+	_, err = convert("a")
+	assert.ErrorContains(t, err, "expected type 'Int32' for field 'age'")
+
+	convert, err = parser.FieldConverter(101)
+	assert.NoError(t, err)
+	vec, err := convert([]any{json.Number("0.1"), json.Number("0.2")})
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.1, 0.2}, vec)
+
+	_, err = parser.FieldConverter(103)
+	assert.ErrorContains(t, err, "field 103 is not found or not provided by rows")
+	_, err = parser.FieldConverter(999)
+	assert.ErrorContains(t, err, "field 999 is not found or not provided by rows")
+}
+
+func TestRowParser_NullArrayElement(t *testing.T) {
+	for _, eleType := range []schemapb.DataType{
+		schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32,
+		schemapb.DataType_Int64, schemapb.DataType_Float, schemapb.DataType_Double,
+	} {
+		schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "arr", DataType: schemapb.DataType_Array, ElementType: eleType})
+		parser, err := NewRowParser(schema)
+		assert.NoError(t, err)
+		_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "arr": [1, null, 3]}`))
+		assert.ErrorContains(t, err, fmt.Sprintf("null elements not allowed in Array<%s>, field 'arr' has null at index 1", eleType.String()))
+	}
+}
+
+func TestRowParser_NullVectorElement(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, null]}`))
+	assert.ErrorContains(t, err, "field 'vector' vector element at index 1 is null")
+	parseErr := &ParseError{}
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, ErrKindInvalidValue, parseErr.Kind)
+
+	// reported before the dim mismatch
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [null, 0.2, 0.3]}`))
+	assert.ErrorContains(t, err, "field 'vector' vector element at index 0 is null")
+}
+
+func TestRowParser_TrimAndDropEmptyArrayElements(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{
+		FieldID:     102,
+		Name:        "tags",
+		DataType:    schemapb.DataType_Array,
+		ElementType: schemapb.DataType_VarChar,
+		TypeParams:  []*commonpb.KeyValuePair{{Key: common.MaxCapacityKey, Value: "3"}},
+	})
+	raw := `{"id": 1, "vector": [0.1, 0.2], "tags": [" a", "", "b ", "  ", "c"]}`
+
+	parser, err := NewRowParser(schema, WithTrimArrayElements(102), WithDropEmptyArrayElements(102))
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, raw))
+	assert.NoError(t, err)
+	// the max_capacity is checked after dropping
+	assert.Equal(t, []string{"a", "b", "c"}, row[102].(*schemapb.ScalarField).GetStringData().GetData())
+
+	// whitespace-only elements are not empty unless they are trimmed
+	parser, err = NewRowParser(schema, WithDropEmptyArrayElements(102))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, raw))
+	assert.ErrorContains(t, err, "the array of field 'tags' has 4 elements, exceeds the max_capacity 3")
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "tags": [" a", "", "  "]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{" a", "  "}, row[102].(*schemapb.ScalarField).GetStringData().GetData())
+
+	parser, err = NewRowParser(schema, WithTrimArrayElements(102))
+	assert.NoError(t, err)
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "tags": [" a", "", "  "]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "", ""}, row[102].(*schemapb.ScalarField).GetStringData().GetData())
+
+	// the max_capacity is only checked along with dropping
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	row, err = parser.Parse(decodeRow(t, raw))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(row[102].(*schemapb.ScalarField).GetStringData().GetData()))
+
+	_, err = NewRowParser(schema, WithTrimArrayElements(100))
+	assert.ErrorContains(t, err, "trim and drop empty are only supported for Array<VarChar> field")
+}
+
+func TestRowParser_MaxVarCharBytes(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{
+			FieldID:    102,
+			Name:       "short",
+			DataType:   schemapb.DataType_VarChar,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.MaxLengthKey, Value: "4"}},
+		},
+		&schemapb.FieldSchema{
+			FieldID:    103,
+			Name:       "long",
+			DataType:   schemapb.DataType_VarChar,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.MaxLengthKey, Value: "100"}},
+		},
+		&schemapb.FieldSchema{
+			FieldID:     104,
+			Name:        "tags",
+			DataType:    schemapb.DataType_Array,
+			ElementType: schemapb.DataType_VarChar,
+			TypeParams:  []*commonpb.KeyValuePair{{Key: common.MaxLengthKey, Value: "100"}},
+		},
+	)
+	parser, err := NewRowParser(schema, WithMaxVarCharBytes(8))
+	assert.NoError(t, err)
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "short": "abcd", "long": "abcdefgh", "tags": ["abcdefgh"]}`))
+	assert.NoError(t, err)
+	// the schema is the binding limit
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "short": "abcde", "long": "a", "tags": []}`))
+	assert.ErrorContains(t, err, "the length 5 of value for field 'short' exceeds max_length 4 of the schema")
+	// the global limit is the binding limit, the length is counted in bytes
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "short": "a", "long": "向量向量", "tags": []}`))
+	assert.ErrorContains(t, err, "the length 12 of value for field 'long' exceeds the global limit 8 bytes of VarChar values")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "short": "a", "long": "a", "tags": ["a", "abcdefghi"]}`))
+	assert.ErrorContains(t, err, "the length 9 of element at index 1 for field 'tags' exceeds the global limit 8 bytes of VarChar values")
+
+	// not checked by default
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "short": "abcde", "long": "a", "tags": []}`))
+	assert.NoError(t, err)
+}
+
+func TestRowParser_JSONStringFields(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "doc", DataType: schemapb.DataType_VarChar})
+	parser, err := NewRowParser(schema, WithJSONStringFields(102))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "doc": "{\"b\": 1.0,  \"a\": [1]}"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"b": 1.0,  "a": [1]}`, row[102])
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "doc": "{\"b\": 1"}`))
+	assert.ErrorContains(t, err, `value of field 'doc' is not valid JSON: '{"b": 1'`)
+
+	_, err = NewRowParser(schema, WithJSONStringFields(100))
+	assert.ErrorContains(t, err, "JSON string is only supported for VarChar field")
+}
+
+func TestRowParser_RejectEmptyStringPK(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "name", DataType: schemapb.DataType_VarChar})
+	schema.Fields[0].DataType = schemapb.DataType_VarChar
+	parser, err := NewRowParser(schema, WithRejectEmptyStringPK())
+	assert.NoError(t, err)
+
+	_, err = parser.Parse(decodeRow(t, `{"id": "a", "vector": [0.1, 0.2], "name": ""}`))
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": "", "vector": [0.1, 0.2], "name": "a"}`))
+	assert.ErrorContains(t, err, "the primary key 'id' is an empty string")
+
+	// empty strings are accepted by default
+	parser, err = NewRowParser(schema)
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, `{"id": "", "vector": [0.1, 0.2], "name": "a"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "", row[100])
+}
+
+func TestRowParser_WarnSimilarDynamicKey(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true})
+	warnings := make(map[string]string)
+	parser, err := NewRowParser(schema, WithWarnSimilarDynamicKey(1, func(key string, fieldName string) {
+		warnings[key] = fieldName
+	}))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "vectr": [0.1, 0.2], "ID": 2, "color": "red"}`))
+	assert.NoError(t, err)
+	// the keys are still stored in the dynamic field
+	assert.JSONEq(t, `{"vectr": [0.1, 0.2], "ID": 2, "color": "red"}`, string(row[102].([]byte)))
+	assert.Equal(t, map[string]string{"vectr": "vector"}, warnings)
+
+	assert.Equal(t, 1, editDistance("vectr", "vector"))
+	assert.Equal(t, 2, editDistance("ID", "id"))
+	assert.Equal(t, 3, editDistance("", "abc"))
+	assert.Equal(t, 1, editDistance("向量", "向"))
+}
+
+func TestRowParser_HexStringPK(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema(), WithHexStringPK())
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": "0x1a2b", "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0x1a2b), row[100])
+	row, err = parser.Parse(decodeRow(t, `{"id": "0X7FFFFFFFFFFFFFFF", "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(math.MaxInt64), row[100])
+	// the numbers are still accepted
+	row, err = parser.Parse(decodeRow(t, `{"id": 6699, "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6699), row[100])
+
+	_, err = parser.Parse(decodeRow(t, `{"id": "6699", "vector": [0.1, 0.2]}`))
+	assert.ErrorContains(t, err, "must be a hexadecimal string with the '0x' prefix, got '6699'")
+	_, err = parser.Parse(decodeRow(t, `{"id": "0x", "vector": [0.1, 0.2]}`))
+	assert.ErrorContains(t, err, "must be a hexadecimal string with the '0x' prefix, got '0x'")
+	_, err = parser.Parse(decodeRow(t, `{"id": "0x1g", "vector": [0.1, 0.2]}`))
+	assert.ErrorContains(t, err, "is not a valid hexadecimal int64 '0x1g'")
+	_, err = parser.Parse(decodeRow(t, `{"id": "0x8000000000000000", "vector": [0.1, 0.2]}`))
+	assert.ErrorContains(t, err, "is not a valid hexadecimal int64")
+
+	// hex strings are parsed in base 16 while the numbers are parsed in base 10
+	parser, err = NewRowParser(newTestSchema(), WithHexStringPK(), WithDecimalIntegers())
+	assert.NoError(t, err)
+	row, err = parser.Parse(decodeRow(t, `{"id": "0x10", "vector": [0.1, 0.2]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(16), row[100])
+	row, err = parser.Parse(map[string]any{"id": json.Number("010"), "vector": []any{json.Number("0.1"), json.Number("0.2")}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), row[100])
+
+	// hex strings are rejected by default
+	parser, err = NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	_, err = parser.Parse(decodeRow(t, `{"id": "0x1a2b", "vector": [0.1, 0.2]}`))
+	assert.Error(t, err)
+}
+
+func TestRowParser_FieldAndDynamicKeys(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "name", DataType: schemapb.DataType_VarChar},
+		&schemapb.FieldSchema{FieldID: 103, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+	)
+	parser, err := NewRowParser(schema, WithAliases(map[string][]string{"name": {"title"}}),
+		WithDynamicFieldMerge(DynamicMergeLastWins))
+	assert.NoError(t, err)
+
+	// a key matching a field binds to the field, the other keys go to the dynamic field
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "title": "a", "Name": "b"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "a", row[102])
+	assert.Equal(t, `{"Name":"b"}`, string(row[103].([]byte)))
+
+	// the field and its alias are duplicates
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": "a", "title": "b"}`))
+	assert.ErrorContains(t, err, "the field 'name' is provided more than once by aliases")
+
+	// a key of the explicit dynamic field cannot rewrite a field
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": "a", "$meta": {"name": "b"}}`))
+	assert.ErrorContains(t, err, "the key 'name' of dynamic field '$meta' collides with the field 'name'")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": "a", "$meta": "{\"title\": \"b\"}"}`))
+	assert.ErrorContains(t, err, "the key 'title' of dynamic field '$meta' collides with the field 'name'")
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "name": "a", "$meta": {"x": 1}, "x": 2}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"x":2}`, string(row[103].([]byte)))
+}
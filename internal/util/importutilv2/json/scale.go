@@ -0,0 +1,109 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// RoundingMode decides how to round the scaled decimal which is not an integer.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds half away from zero, e.g. 0.5 => 1 and -0.5 => -1.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds half to the even neighbor, e.g. 0.5 => 0 and 1.5 => 2.
+	RoundHalfEven
+	// RoundDown truncates toward zero.
+	RoundDown
+	// RoundExact fails the row if the scaled decimal is not an integer.
+	RoundExact
+)
+
+type scaleOption struct {
+	factor int64
+	mode   RoundingMode
+}
+
+func (r *rowParser) checkScaleFields() error {
+	for fieldID, scale := range r.option.scaleFields {
+		field := r.id2Field[fieldID]
+		if field.GetDataType() != schemapb.DataType_Int64 {
+			return merr.WrapErrImportFailed(fmt.Sprintf("scale is only supported for Int64 field, field id: %d", fieldID))
+		}
+		if scale.factor <= 0 {
+			return merr.WrapErrImportFailed(fmt.Sprintf("invalid scale factor %d of field '%s'", scale.factor, field.GetName()))
+		}
+		if scale.mode < RoundHalfUp || scale.mode > RoundExact {
+			return merr.WrapErrImportFailed(fmt.Sprintf("unsupported rounding mode %d of field '%s'", scale.mode, field.GetName()))
+		}
+	}
+	return nil
+}
+
+// scaleToInt multiplies the decimal, given as a number or a string, by the factor of the field, and
+// rounds the result to an integer. The decimal is scaled exactly, "12.34" is 1234 cents rather than
+// 12.34*100 in floating point.
+func (r *rowParser) scaleToInt(obj any, fieldID int64) (any, error) {
+	field := r.id2Field[fieldID]
+	str, ok := obj.(string)
+	if !ok {
+		num, ok := r.asNumber(obj)
+		if !ok {
+			return obj, nil
+		}
+		str = num.String()
+	}
+	invalid := func(reason string) error {
+		return newParseError(ErrKindInvalidValue, field, str, merr.WrapErrImportFailed(
+			fmt.Sprintf("cannot scale '%s' of field '%s' to an integer, %s", str, field.GetName(), reason)))
+	}
+	// json.Valid rejects the forms which are not JSON numbers, such as "NaN" and "0x1p-2"
+	if _, err := strconv.ParseFloat(str, 64); err != nil || !json.Valid([]byte(str)) {
+		return nil, invalid("it's not a decimal")
+	}
+	scale := r.option.scaleFields[fieldID]
+	rat, _ := new(big.Rat).SetString(str)
+	rat.Mul(rat, new(big.Rat).SetInt64(scale.factor))
+	quo, rem := new(big.Int).QuoRem(rat.Num(), rat.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		// compare the remainder with half of the denominator
+		cmp := new(big.Int).Lsh(new(big.Int).Abs(rem), 1).Cmp(rat.Denom())
+		roundAway := false
+		switch scale.mode {
+		case RoundExact:
+			return nil, invalid(fmt.Sprintf("the scaled value %s is not exact", rat.FloatString(6)))
+		case RoundHalfUp:
+			roundAway = cmp >= 0
+		case RoundHalfEven:
+			roundAway = cmp > 0 || (cmp == 0 && quo.Bit(0) == 1)
+		}
+		if roundAway {
+			quo.Add(quo, big.NewInt(int64(rat.Sign())))
+		}
+	}
+	if !quo.IsInt64() {
+		return nil, invalid(fmt.Sprintf("the scaled value %s overflows Int64", quo.String()))
+	}
+	return json.Number(quo.String()), nil
+}
@@ -0,0 +1,78 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestRowParser_ScaleToInt(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "cents", DataType: schemapb.DataType_Int64})
+	parse := func(mode RoundingMode, value string) (any, error) {
+		parser, err := NewRowParser(schema, WithScaleToInt(102, 100, mode))
+		assert.NoError(t, err)
+		row, err := parser.Parse(decodeRow(t, fmt.Sprintf(`{"id": 1, "vector": [0.1, 0.2], "cents": %s}`, value)))
+		if err != nil {
+			return nil, err
+		}
+		return row[102], nil
+	}
+
+	cases := []struct {
+		value    string
+		halfUp   int64
+		halfEven int64
+		down     int64
+	}{
+		{`"12.34"`, 1234, 1234, 1234},
+		{`12.34`, 1234, 1234, 1234},
+		{`"7"`, 700, 700, 700},
+		{`"0.125"`, 13, 12, 12},
+		{`"0.135"`, 14, 14, 13},
+		{`"-0.125"`, -13, -12, -12},
+		{`"1.2351"`, 124, 124, 123},
+	}
+	for _, c := range cases {
+		for mode, expected := range map[RoundingMode]int64{RoundHalfUp: c.halfUp, RoundHalfEven: c.halfEven, RoundDown: c.down} {
+			value, err := parse(mode, c.value)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, value, "value %s, mode %d", c.value, mode)
+		}
+	}
+
+	value, err := parse(RoundExact, `"12.34"`)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1234), value)
+	_, err = parse(RoundExact, `"12.345"`)
+	assert.ErrorContains(t, err, "cannot scale '12.345' of field 'cents' to an integer, the scaled value 1234.500000 is not exact")
+	_, err = parse(RoundHalfUp, `"12,34"`)
+	assert.ErrorContains(t, err, "it's not a decimal")
+	_, err = parse(RoundHalfUp, `"1e20"`)
+	assert.ErrorContains(t, err, "overflows Int64")
+
+	_, err = NewRowParser(schema, WithScaleToInt(101, 100, RoundHalfUp))
+	assert.ErrorContains(t, err, "scale is only supported for Int64 field")
+	_, err = NewRowParser(schema, WithScaleToInt(102, 0, RoundHalfUp))
+	assert.ErrorContains(t, err, "invalid scale factor 0 of field 'cents'")
+	_, err = NewRowParser(schema, WithScaleToInt(102, 100, RoundingMode(9)))
+	assert.ErrorContains(t, err, "unsupported rounding mode 9")
+}
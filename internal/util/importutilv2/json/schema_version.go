@@ -0,0 +1,71 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func (r *rowParser) checkSchemaVersionKey() error {
+	key := r.option.schemaVersionKey
+	if key == "" {
+		return nil
+	}
+	if _, ok := r.name2FieldID[key]; ok || key == r.pkField.GetName() {
+		return merr.WrapErrImportFailed(fmt.Sprintf("the schema version key '%s' conflicts with the field of the same name", key))
+	}
+	if _, ok := r.aliasOf(key); ok {
+		return merr.WrapErrImportFailed(fmt.Sprintf("the schema version key '%s' conflicts with the alias of the same name", key))
+	}
+	if key == r.option.deleteMarker {
+		return merr.WrapErrImportFailed(fmt.Sprintf("the schema version key '%s' conflicts with the delete marker", key))
+	}
+	return nil
+}
+
+// checkRowSchemaVersion checks the schema version of the row, given as a string or a number,
+// against the expected version.
+func (r *rowParser) checkRowSchemaVersion(stringMap map[string]any) error {
+	key := r.option.schemaVersionKey
+	value, ok := stringMap[key]
+	if !ok {
+		return newParseError(ErrKindMissingField, nil, nil, merr.WrapErrImportFailed(
+			fmt.Sprintf("the schema version '%s' is missed, expected version '%s'", key, r.option.schemaVersion)))
+	}
+	var version string
+	switch v := value.(type) {
+	case string:
+		version = v
+	case json.Number:
+		version = v.String()
+	default:
+		num, ok := r.asNumber(v)
+		if !ok {
+			return newParseError(ErrKindTypeMismatch, nil, value, merr.WrapErrImportFailed(
+				fmt.Sprintf("the schema version '%s' should be a string or a number, got '%v'", key, value)))
+		}
+		version = num.String()
+	}
+	if version != r.option.schemaVersion {
+		return newParseError(ErrKindInvalidValue, nil, value, merr.WrapErrImportFailed(
+			fmt.Sprintf("the schema version '%s' of the row is incompatible with the expected version '%s'", version, r.option.schemaVersion)))
+	}
+	return nil
+}
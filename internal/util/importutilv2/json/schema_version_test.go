@@ -0,0 +1,53 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestRowParser_SchemaVersion(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true})
+	parser, err := NewRowParser(schema, WithSchemaVersion("_schema_version", "3"))
+	assert.NoError(t, err)
+
+	// the key is not stored in the dynamic field
+	for _, raw := range []string{
+		`{"_schema_version": "3", "id": 1, "vector": [0.1, 0.2], "x": 1}`,
+		`{"_schema_version": 3, "id": 1, "vector": [0.1, 0.2], "x": 1}`,
+	} {
+		row, err := parser.Parse(decodeRow(t, raw))
+		assert.NoError(t, err)
+		assert.Equal(t, `{"x":1}`, string(row[102].([]byte)))
+	}
+
+	_, err = parser.Parse(decodeRow(t, `{"_schema_version": "2", "id": 1, "vector": [0.1, 0.2]}`))
+	assert.ErrorContains(t, err, "the schema version '2' of the row is incompatible with the expected version '3'")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2]}`))
+	assert.ErrorContains(t, err, "the schema version '_schema_version' is missed, expected version '3'")
+	_, err = parser.Parse(decodeRow(t, `{"_schema_version": [3], "id": 1, "vector": [0.1, 0.2]}`))
+	assert.ErrorContains(t, err, "should be a string or a number")
+
+	_, err = NewRowParser(schema, WithSchemaVersion("id", "3"))
+	assert.ErrorContains(t, err, "conflicts with the field of the same name")
+	_, err = NewRowParser(schema, WithSchemaVersion("_v", "3"), WithDeleteMarker("_v"))
+	assert.ErrorContains(t, err, "conflicts with the delete marker")
+}
@@ -0,0 +1,68 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+// editDistance returns the Levenshtein distance between the two strings, counted in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(values ...int) int {
+	res := values[0]
+	for _, v := range values[1:] {
+		if v < res {
+			res = v
+		}
+	}
+	return res
+}
+
+// warnSimilarKey reports the dynamic key which is likely a typo of a field name,
+// since such a key is silently stored in the dynamic field. The closest field name
+// is reported, ties are broken by the name to keep the warning stable.
+func (r *rowParser) warnSimilarKey(key string) {
+	if r.option.warnSimilarKey == nil {
+		return
+	}
+	closest, distance := "", r.option.similarKeyDistance+1
+	for name := range r.name2FieldID {
+		d := editDistance(key, name)
+		if d < distance || (d == distance && name < closest) {
+			closest, distance = name, d
+		}
+	}
+	if closest != "" {
+		r.option.warnSimilarKey(key, closest)
+	}
+}
@@ -0,0 +1,56 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+// EstimateValueSize returns the approximate size in bytes of a parsed value.
+func EstimateValueSize(value any) int {
+	switch v := value.(type) {
+	case bool, int8:
+		return 1
+	case int16:
+		return 2
+	case int32, float32:
+		return 4
+	case int64, float64:
+		return 8
+	case string:
+		return len(v)
+	case []byte:
+		return len(v)
+	case []float32:
+		return len(v) * 4
+	case *schemapb.ScalarField:
+		return proto.Size(v)
+	default:
+		return 0
+	}
+}
+
+// EstimateRowSize returns the approximate size in bytes of a parsed row.
+func EstimateRowSize(row Row) int {
+	size := 0
+	for _, value := range row {
+		size += EstimateValueSize(value)
+	}
+	return size
+}
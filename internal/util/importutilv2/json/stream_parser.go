@@ -0,0 +1,288 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+type StreamParserOption func(opt *streamParserOption)
+
+type streamParserOption struct {
+	checkpoint func(rowIndex int64, byteOffset int64)
+	skipRows   int64
+	seekOffset int64
+	seekRow    int64
+
+	allowTrailingComments bool
+
+	sampleEvery  int64
+	sampleReport func(sampled int64, skipped int64)
+}
+
+// WithCheckpoint sets a callback which is fired after each row is emitted, with the index
+// of the row and the byte offset right after it. They can be used to resume the parsing.
+func WithCheckpoint(fn func(rowIndex int64, byteOffset int64)) StreamParserOption {
+	return func(opt *streamParserOption) {
+		opt.checkpoint = fn
+	}
+}
+
+// WithSkipRows skips the first n rows without parsing them.
+func WithSkipRows(n int64) StreamParserOption {
+	return func(opt *streamParserOption) {
+		opt.skipRows = n
+	}
+}
+
+// WithSeekOffset resumes the parsing from a checkpoint, the byte offset and the
+// row index are the values reported by the checkpoint callback.
+func WithSeekOffset(byteOffset int64, rowIndex int64) StreamParserOption {
+	return func(opt *streamParserOption) {
+		opt.seekOffset = byteOffset
+		opt.seekRow = rowIndex + 1
+	}
+}
+
+// WithTrailingComments allows a comment starting with "//" or "#" after the row on the same line,
+// any other trailing content is rejected.
+func WithTrailingComments() StreamParserOption {
+	return func(opt *streamParserOption) {
+		opt.allowTrailingComments = true
+	}
+}
+
+// WithSampleEvery only parses and emits every nth row, i.e. the rows whose index is a multiple of n,
+// the other rows are skipped without being decoded. report is called with the numbers of the sampled
+// and the skipped rows when the end of the stream is reached, it can be nil.
+func WithSampleEvery(n int64, report func(sampled int64, skipped int64)) StreamParserOption {
+	return func(opt *streamParserOption) {
+		opt.sampleEvery = n
+		opt.sampleReport = report
+	}
+}
+
+// StreamParser parses rows from JSON lines, i.e. one JSON object per line.
+type StreamParser struct {
+	parser RowParser
+	option *streamParserOption
+}
+
+func NewStreamParser(parser RowParser, opts ...StreamParserOption) *StreamParser {
+	option := &streamParserOption{}
+	for _, opt := range opts {
+		opt(option)
+	}
+	return &StreamParser{
+		parser: parser,
+		option: option,
+	}
+}
+
+// lineReader reads the lines of the stream and tracks the position.
+type lineReader struct {
+	reader   *bufio.Reader
+	offset   int64
+	lineNum  int64
+	rowIndex int64
+}
+
+// next returns the next non-empty line, or io.EOF at the end of the stream.
+func (l *lineReader) next() ([]byte, error) {
+	for {
+		line, err := l.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("failed to read row %d at line %d, error: %v", l.rowIndex, l.lineNum+1, err))
+		}
+		if len(line) == 0 && err == io.EOF {
+			return nil, io.EOF
+		}
+		l.offset += int64(len(line))
+		l.lineNum++
+		if len(bytes.TrimSpace(line)) > 0 {
+			return line, nil
+		}
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+	}
+}
+
+func (s *StreamParser) newLineReader(r io.Reader) (*lineReader, error) {
+	if s.option.seekOffset > 0 {
+		if seeker, ok := r.(io.Seeker); ok {
+			if _, err := seeker.Seek(s.option.seekOffset, io.SeekStart); err != nil {
+				return nil, merr.WrapErrImportFailed(fmt.Sprintf("failed to seek to offset %d, error: %v", s.option.seekOffset, err))
+			}
+		} else if _, err := io.CopyN(io.Discard, r, s.option.seekOffset); err != nil {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("failed to seek to offset %d, error: %v", s.option.seekOffset, err))
+		}
+	}
+	// line numbers are unknown after seeking, they are counted from the offset
+	return &lineReader{
+		reader:   bufio.NewReader(r),
+		offset:   s.option.seekOffset,
+		rowIndex: s.option.seekRow,
+	}, nil
+}
+
+// decodeValue decodes a JSON value, the numbers are decoded as json.Number.
+func decodeValue(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// decodeLine decodes the JSON value of the line, and returns the offset where the value ends.
+func decodeLine(line []byte) (any, int64, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, 0, err
+	}
+	return value, dec.InputOffset(), nil
+}
+
+// trailingData returns the position and a snippet of the content after the end of the
+// JSON value, which is neither whitespace nor an allowed comment.
+func (s *StreamParser) trailingData(line []byte, end int64) (int64, []byte) {
+	rest := line[end:]
+	trimmed := bytes.TrimLeft(rest, " \t\r\n")
+	if len(trimmed) == 0 {
+		return 0, nil
+	}
+	if s.option.allowTrailingComments && (bytes.HasPrefix(trimmed, []byte("//")) || bytes.HasPrefix(trimmed, []byte("#"))) {
+		return 0, nil
+	}
+	snippet := bytes.TrimSpace(trimmed)
+	if len(snippet) > 16 {
+		snippet = snippet[:16]
+	}
+	return end + int64(len(rest)-len(trimmed)), snippet
+}
+
+// ParseStream parses the rows one by one and calls emit for each of them,
+// it stops at the first invalid row or at the first error returned by emit.
+func (s *StreamParser) ParseStream(r io.Reader, emit func(Row) error) error {
+	return s.parseStream(r, func(rowIndex int64, byteOffset int64, row Row) error {
+		if err := emit(row); err != nil {
+			return err
+		}
+		if s.option.checkpoint != nil {
+			s.option.checkpoint(rowIndex, byteOffset)
+		}
+		return nil
+	})
+}
+
+// ParseStreamBatched parses the rows and calls emit for each batchSize rows, the last
+// batch may be smaller. The slice passed to emit is reused, it must not be retained
+// after emit returns. The checkpoint is fired after each batch is emitted.
+func (s *StreamParser) ParseStreamBatched(r io.Reader, batchSize int, emit func([]Row) error) error {
+	if batchSize <= 0 {
+		return merr.WrapErrImportFailed(fmt.Sprintf("invalid batch size %d", batchSize))
+	}
+	batch := make([]Row, 0, batchSize)
+	var lastIndex, lastOffset int64
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := emit(batch); err != nil {
+			return merr.WrapErrImportFailed(fmt.Sprintf("failed to emit rows [%d, %d], error: %v",
+				lastIndex-int64(len(batch))+1, lastIndex, err))
+		}
+		if s.option.checkpoint != nil {
+			s.option.checkpoint(lastIndex, lastOffset)
+		}
+		batch = batch[:0]
+		return nil
+	}
+	err := s.parseStream(r, func(rowIndex int64, byteOffset int64, row Row) error {
+		batch = append(batch, row)
+		lastIndex, lastOffset = rowIndex, byteOffset
+		if len(batch) < batchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+func (s *StreamParser) parseStream(r io.Reader, emit func(rowIndex int64, byteOffset int64, row Row) error) error {
+	if s.option.sampleEvery < 0 {
+		return merr.WrapErrImportFailed(fmt.Sprintf("invalid sample interval %d", s.option.sampleEvery))
+	}
+	lines, err := s.newLineReader(r)
+	if err != nil {
+		return err
+	}
+	var sampled, skipped int64
+	for ; ; lines.rowIndex++ {
+		line, err := lines.next()
+		if err == io.EOF {
+			if s.option.sampleEvery > 0 && s.option.sampleReport != nil {
+				s.option.sampleReport(sampled, skipped)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if lines.rowIndex < s.option.skipRows {
+			continue
+		}
+		if s.option.sampleEvery > 0 {
+			if lines.rowIndex%s.option.sampleEvery != 0 {
+				skipped++
+				continue
+			}
+			sampled++
+		}
+		value, end, err := decodeLine(line)
+		if err != nil {
+			return merr.WrapErrImportFailed(fmt.Sprintf("failed to decode row %d at line %d, error: %v",
+				lines.rowIndex, lines.lineNum, err))
+		}
+		if pos, trailing := s.trailingData(line, end); trailing != nil {
+			return merr.WrapErrImportFailed(fmt.Sprintf("unexpected trailing data '%s' after row %d at line %d, offset %d",
+				trailing, lines.rowIndex, lines.lineNum, lines.offset-int64(len(line))+pos))
+		}
+		row, err := s.parser.Parse(value)
+		if err != nil {
+			return withRowIndex(err, int(lines.rowIndex), merr.WrapErrImportFailed(fmt.Sprintf("failed to parse row %d at line %d, error: %v",
+				lines.rowIndex, lines.lineNum, err)))
+		}
+		if err = emit(lines.rowIndex, lines.offset, row); err != nil {
+			return err
+		}
+	}
+}
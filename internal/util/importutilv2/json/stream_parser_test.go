@@ -0,0 +1,185 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLines(n int) string {
+	lines := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		lines = append(lines, fmt.Sprintf(`{"id": %d, "vector": [0.1, 0.2]}`, i))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func collectIDs(t *testing.T, sp *StreamParser, data string) []int64 {
+	ids := make([]int64, 0)
+	err := sp.ParseStream(strings.NewReader(data), func(row Row) error {
+		ids = append(ids, row[100].(int64))
+		return nil
+	})
+	assert.NoError(t, err)
+	return ids
+}
+
+func TestStreamParser_Checkpoint(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	data := newTestLines(5)
+
+	type checkpoint struct {
+		rowIndex   int64
+		byteOffset int64
+	}
+	checkpoints := make([]checkpoint, 0)
+	sp := NewStreamParser(parser, WithCheckpoint(func(rowIndex int64, byteOffset int64) {
+		checkpoints = append(checkpoints, checkpoint{rowIndex, byteOffset})
+	}))
+	assert.Equal(t, []int64{0, 1, 2, 3, 4}, collectIDs(t, sp, data))
+	assert.Equal(t, 5, len(checkpoints))
+	assert.Equal(t, int64(4), checkpoints[4].rowIndex)
+	assert.Equal(t, int64(len(data)), checkpoints[4].byteOffset)
+
+	// resume after row 1 with a seekable reader
+	last := checkpoints[1]
+	checkpoints = checkpoints[:0]
+	sp = NewStreamParser(parser, WithSeekOffset(last.byteOffset, last.rowIndex), WithCheckpoint(func(rowIndex int64, byteOffset int64) {
+		checkpoints = append(checkpoints, checkpoint{rowIndex, byteOffset})
+	}))
+	assert.Equal(t, []int64{2, 3, 4}, collectIDs(t, sp, data))
+	assert.Equal(t, checkpoint{4, int64(len(data))}, checkpoints[2])
+
+	// resume with a reader which cannot seek
+	ids := make([]int64, 0)
+	err = sp.ParseStream(bytes.NewBufferString(data), func(row Row) error {
+		ids = append(ids, row[100].(int64))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 3, 4}, ids)
+
+	sp = NewStreamParser(parser, WithSkipRows(3))
+	assert.Equal(t, []int64{3, 4}, collectIDs(t, sp, data))
+}
+
+func TestStreamParser_Error(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	sp := NewStreamParser(parser)
+
+	// empty lines are not rows
+	data := "\n" + `{"id": 1, "vector": [0.1, 0.2]}` + "\n\n" + `{"id": 2, "vector": [0.1]}`
+	err = sp.ParseStream(strings.NewReader(data), func(row Row) error { return nil })
+	assert.ErrorContains(t, err, "failed to parse row 1 at line 4")
+
+	err = sp.ParseStream(strings.NewReader(`{"id": 1,`), func(row Row) error { return nil })
+	assert.ErrorContains(t, err, "failed to decode row 0 at line 1")
+
+	err = sp.ParseStream(strings.NewReader(newTestLines(2)), func(row Row) error { return fmt.Errorf("mock error") })
+	assert.ErrorContains(t, err, "mock error")
+}
+
+func TestStreamParser_Batched(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	data := newTestLines(5)
+
+	batches := make([][]int64, 0)
+	offsets := make([]int64, 0)
+	sp := NewStreamParser(parser, WithCheckpoint(func(rowIndex int64, byteOffset int64) {
+		offsets = append(offsets, rowIndex)
+	}))
+	err = sp.ParseStreamBatched(strings.NewReader(data), 2, func(rows []Row) error {
+		ids := make([]int64, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row[100].(int64))
+		}
+		batches = append(batches, ids)
+		return nil
+	})
+	assert.NoError(t, err)
+	// the last partial batch is flushed
+	assert.Equal(t, [][]int64{{0, 1}, {2, 3}, {4}}, batches)
+	assert.Equal(t, []int64{1, 3, 4}, offsets)
+
+	count := 0
+	err = sp.ParseStreamBatched(strings.NewReader(data), 2, func(rows []Row) error {
+		count++
+		if count == 2 {
+			return fmt.Errorf("mock error")
+		}
+		return nil
+	})
+	assert.ErrorContains(t, err, "failed to emit rows [2, 3], error: mock error")
+
+	err = sp.ParseStreamBatched(strings.NewReader(data), 0, func(rows []Row) error { return nil })
+	assert.ErrorContains(t, err, "invalid batch size 0")
+}
+
+func TestStreamParser_TrailingData(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	row := `{"id": 1, "vector": [0.1, 0.2]}`
+
+	sp := NewStreamParser(parser)
+	err = sp.ParseStream(strings.NewReader(row+" \t\r\n"+row+"\n"), func(row Row) error { return nil })
+	assert.NoError(t, err)
+
+	// the offset is counted from the beginning of the stream
+	data := row + "\n" + row + ` {"id": 2}` + "\n"
+	err = sp.ParseStream(strings.NewReader(data), func(row Row) error { return nil })
+	assert.ErrorContains(t, err, fmt.Sprintf("unexpected trailing data '{\"id\": 2}' after row 1 at line 2, offset %d", 2*len(row)+2))
+
+	err = sp.ParseStream(strings.NewReader(row+" // comment\n"), func(row Row) error { return nil })
+	assert.ErrorContains(t, err, fmt.Sprintf("unexpected trailing data '// comment' after row 0 at line 1, offset %d", len(row)+1))
+
+	sp = NewStreamParser(parser, WithTrailingComments())
+	err = sp.ParseStream(strings.NewReader(row+" // comment\n"+row+"# comment\n"), func(row Row) error { return nil })
+	assert.NoError(t, err)
+	err = sp.ParseStream(strings.NewReader(row+", garbage\n"), func(row Row) error { return nil })
+	assert.ErrorContains(t, err, "unexpected trailing data ', garbage' after row 0 at line 1")
+}
+
+func TestStreamParser_SampleEvery(t *testing.T) {
+	parser, err := NewRowParser(newTestSchema())
+	assert.NoError(t, err)
+	// the skipped rows are not decoded
+	data := newTestLines(4) + "invalid\n" + newTestLines(3)
+
+	var sampled, skipped int64
+	sp := NewStreamParser(parser, WithSampleEvery(3, func(s int64, k int64) {
+		sampled, skipped = s, k
+	}))
+	assert.Equal(t, []int64{0, 3, 1}, collectIDs(t, sp, data))
+	assert.Equal(t, int64(3), sampled)
+	assert.Equal(t, int64(5), skipped)
+
+	sp = NewStreamParser(parser, WithSampleEvery(1, nil))
+	err = sp.ParseStream(strings.NewReader(data), func(row Row) error { return nil })
+	assert.ErrorContains(t, err, "failed to decode row 4 at line 5")
+
+	sp = NewStreamParser(parser, WithSampleEvery(-1, nil))
+	err = sp.ParseStream(strings.NewReader(data), func(row Row) error { return nil })
+	assert.ErrorContains(t, err, "invalid sample interval -1")
+}
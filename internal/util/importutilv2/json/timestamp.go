@@ -0,0 +1,105 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+// TimeUnit is the unit of the epoch time stored for the timestamp strings.
+type TimeUnit string
+
+const (
+	TimeUnitSecond      TimeUnit = "s"
+	TimeUnitMillisecond TimeUnit = "ms"
+	TimeUnitMicrosecond TimeUnit = "us"
+	TimeUnitNanosecond  TimeUnit = "ns"
+)
+
+type timestampOption struct {
+	layout string
+	unit   TimeUnit
+}
+
+func (r *rowParser) checkTimestampFields() error {
+	for fieldID, ts := range r.option.timestampFields {
+		field := r.id2Field[fieldID]
+		if field.GetDataType() != schemapb.DataType_Int64 &&
+			(field.GetDataType() != schemapb.DataType_Array || field.GetElementType() != schemapb.DataType_Int64) {
+			return merr.WrapErrImportFailed(fmt.Sprintf("timestamp is only supported for Int64 and Array<Int64> field, field id: %d", fieldID))
+		}
+		switch ts.unit {
+		case TimeUnitSecond, TimeUnitMillisecond, TimeUnitMicrosecond, TimeUnitNanosecond:
+		default:
+			return merr.WrapErrImportFailed(fmt.Sprintf("unsupported time unit '%s' of field '%s'", ts.unit, field.GetName()))
+		}
+	}
+	return nil
+}
+
+// convertTimestamps converts the timestamp strings of the value, or of the elements
+// of an array value, to the epoch time in the configured unit.
+func (r *rowParser) convertTimestamps(obj any, fieldID int64) (any, error) {
+	ts := r.option.timestampFields[fieldID]
+	switch v := obj.(type) {
+	case string:
+		return r.convertTimestamp(v, ts, fieldID)
+	case []any:
+		res := make([]any, len(v))
+		for i, elem := range v {
+			str, ok := elem.(string)
+			if !ok {
+				res[i] = elem
+				continue
+			}
+			num, err := r.convertTimestamp(str, ts, fieldID)
+			if err != nil {
+				return nil, err
+			}
+			res[i] = num
+		}
+		return res, nil
+	default:
+		return obj, nil
+	}
+}
+
+func (r *rowParser) convertTimestamp(str string, ts timestampOption, fieldID int64) (json.Number, error) {
+	t, err := time.Parse(ts.layout, str)
+	if err != nil {
+		return "", newParseError(ErrKindInvalidValue, r.id2Field[fieldID], str, merr.WrapErrImportFailed(
+			fmt.Sprintf("invalid timestamp '%s' for field '%s', expected layout '%s'", str, r.id2Field[fieldID].GetName(), ts.layout)))
+	}
+	var epoch int64
+	switch ts.unit {
+	case TimeUnitSecond:
+		epoch = t.Unix()
+	case TimeUnitMillisecond:
+		epoch = t.UnixMilli()
+	case TimeUnitMicrosecond:
+		epoch = t.UnixMicro()
+	default:
+		epoch = t.UnixNano()
+	}
+	return json.Number(strconv.FormatInt(epoch, 10)), nil
+}
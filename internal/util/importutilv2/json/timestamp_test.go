@@ -0,0 +1,62 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestRowParser_Timestamp(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "ts", DataType: schemapb.DataType_Int64},
+		&schemapb.FieldSchema{FieldID: 103, Name: "tss", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_Int64},
+	)
+	for _, c := range []struct {
+		unit   TimeUnit
+		expect int64
+	}{
+		{TimeUnitSecond, 1700000000},
+		{TimeUnitMillisecond, 1700000000123},
+		{TimeUnitMicrosecond, 1700000000123456},
+		{TimeUnitNanosecond, 1700000000123456789},
+	} {
+		parser, err := NewRowParser(schema, WithTimestamp(102, "", c.unit), WithTimestamp(103, "", c.unit))
+		assert.NoError(t, err)
+		row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "ts": "2023-11-14T22:13:20.123456789Z",
+			"tss": ["2023-11-14T22:13:20.123456789Z", 5]}`))
+		assert.NoError(t, err)
+		assert.Equal(t, c.expect, row[102])
+		assert.Equal(t, []int64{c.expect, 5}, row[103].(*schemapb.ScalarField).GetLongData().GetData())
+	}
+
+	parser, err := NewRowParser(schema, WithTimestamp(102, "2006-01-02", TimeUnitSecond))
+	assert.NoError(t, err)
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "ts": "1970-01-02", "tss": []}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(86400), row[102])
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "ts": "1970/01/02", "tss": []}`))
+	assert.ErrorContains(t, err, "invalid timestamp '1970/01/02' for field 'ts', expected layout '2006-01-02'")
+
+	_, err = NewRowParser(schema, WithTimestamp(101, "", TimeUnitSecond))
+	assert.ErrorContains(t, err, "timestamp is only supported for Int64 and Array<Int64> field")
+	_, err = NewRowParser(schema, WithTimestamp(102, "", "min"))
+	assert.ErrorContains(t, err, "unsupported time unit 'min' of field 'ts'")
+}
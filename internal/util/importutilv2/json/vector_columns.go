@@ -0,0 +1,83 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"fmt"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func (r *rowParser) checkVectorColumns() error {
+	for fieldID, columns := range r.option.vectorColumns {
+		field, ok := r.id2Field[fieldID]
+		if !ok || field.GetDataType() != schemapb.DataType_FloatVector {
+			return merr.WrapErrImportFailed(fmt.Sprintf("vector columns are only supported for FloatVector field, field id: %d", fieldID))
+		}
+		if len(columns) != r.dims[fieldID] || len(lo.Uniq(columns)) != len(columns) {
+			return merr.WrapErrImportFailed(fmt.Sprintf("field '%s' expects %d distinct columns, got %v",
+				field.GetName(), r.dims[fieldID], columns))
+		}
+		for _, column := range columns {
+			if _, ok := r.name2FieldID[column]; ok {
+				return merr.WrapErrImportFailed(fmt.Sprintf("the column '%s' of vector field '%s' conflicts with the field of the same name",
+					column, field.GetName()))
+			}
+		}
+	}
+	return nil
+}
+
+// assembleVectorColumns returns a copy of the row where the columns of each vector field are
+// replaced by the vector. The row is returned as is if none of the columns is provided.
+func (r *rowParser) assembleVectorColumns(stringMap map[string]any) (map[string]any, error) {
+	res, copied := stringMap, false
+	for fieldID, columns := range r.option.vectorColumns {
+		name := r.id2Field[fieldID].GetName()
+		missing := lo.Filter(columns, func(column string, _ int) bool {
+			_, ok := stringMap[column]
+			return !ok
+		})
+		if len(missing) == len(columns) {
+			continue
+		}
+		if len(missing) > 0 {
+			return nil, newParseError(ErrKindMissingField, r.id2Field[fieldID], nil, merr.WrapErrImportFailed(
+				fmt.Sprintf("columns %v of vector field '%s' are missed, %d of %d columns are provided",
+					missing, name, len(columns)-len(missing), len(columns))))
+		}
+		if _, ok := r.lookupValue(stringMap, name); ok {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("the vector field '%s' is provided by both the field and the columns", name))
+		}
+		if !copied {
+			res, copied = make(map[string]any, len(stringMap)), true
+			for key, value := range stringMap {
+				res[key] = value
+			}
+		}
+		vec := make([]any, len(columns))
+		for i, column := range columns {
+			vec[i] = stringMap[column]
+			delete(res, column)
+		}
+		res[name] = vec
+	}
+	return res, nil
+}
@@ -0,0 +1,60 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestRowParser_VectorColumns(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true})
+	schema.Fields[1].TypeParams[0].Value = "3"
+	parser, err := NewRowParser(schema, WithVectorColumns(101, "v0", "v1", "v2"))
+	assert.NoError(t, err)
+
+	raw := decodeRow(t, `{"id": 1, "v2": 0.3, "v0": 0.1, "v1": 0.2, "x": 1}`)
+	row, err := parser.Parse(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.1, 0.2, 0.3}, row[101])
+	// the columns are not stored in the dynamic field, and the input is not modified
+	assert.Equal(t, `{"x":1}`, string(row[102].([]byte)))
+	assert.Equal(t, 5, len(raw.(map[string]any)))
+
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2, 0.3]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.1, 0.2, 0.3}, row[101])
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "v0": 0.1, "v2": 0.3}`))
+	assert.ErrorContains(t, err, "columns [v1] of vector field 'vector' are missed, 2 of 3 columns are provided")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "v0": 0.1, "v1": 0.2, "v2": 0.3, "vector": [0.1, 0.2, 0.3]}`))
+	assert.ErrorContains(t, err, "the vector field 'vector' is provided by both the field and the columns")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "v0": 0.1, "v1": "a", "v2": 0.3}`))
+	assert.ErrorContains(t, err, "expected type 'FloatVector' for field 'vector'")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1}`))
+	assert.ErrorContains(t, err, "value of field 'vector' is missed")
+
+	_, err = NewRowParser(schema, WithVectorColumns(101, "v0", "v1"))
+	assert.ErrorContains(t, err, "field 'vector' expects 3 distinct columns, got [v0 v1]")
+	_, err = NewRowParser(schema, WithVectorColumns(101, "v0", "id", "v2"))
+	assert.ErrorContains(t, err, "the column 'id' of vector field 'vector' conflicts with the field of the same name")
+	_, err = NewRowParser(schema, WithVectorColumns(100, "v0"))
+	assert.ErrorContains(t, err, "vector columns are only supported for FloatVector field")
+}
@@ -0,0 +1,98 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus/pkg/util/merr"
+	"github.com/milvus-io/milvus/pkg/util/typeutil"
+)
+
+func (r *rowParser) checkVectorContainer() error {
+	key := r.option.vectorContainer
+	if key == "" {
+		return nil
+	}
+	if _, ok := r.name2FieldID[key]; ok {
+		return merr.WrapErrImportFailed(fmt.Sprintf("the vector container '%s' conflicts with the field of the same name", key))
+	}
+	if len(r.option.containerFields) == 0 {
+		for fieldID, field := range r.id2Field {
+			if typeutil.IsVectorType(field.GetDataType()) {
+				r.option.containerFields = append(r.option.containerFields, fieldID)
+			}
+		}
+		sort.Slice(r.option.containerFields, func(i, j int) bool {
+			return r.option.containerFields[i] < r.option.containerFields[j]
+		})
+	}
+	for _, fieldID := range r.option.containerFields {
+		field, ok := r.id2Field[fieldID]
+		if !ok || !typeutil.IsVectorType(field.GetDataType()) {
+			return merr.WrapErrImportFailed(fmt.Sprintf("vector container is only supported for vector field, field id: %d", fieldID))
+		}
+	}
+	return nil
+}
+
+// expandVectorContainer returns a copy of the row where the container object is replaced by the
+// vector fields it provides. The row is returned as is if it doesn't have the container key.
+func (r *rowParser) expandVectorContainer(stringMap map[string]any) (map[string]any, error) {
+	key := r.option.vectorContainer
+	value, ok := stringMap[key]
+	if !ok {
+		return stringMap, nil
+	}
+	container, ok := value.(map[string]any)
+	if !ok {
+		return nil, merr.WrapErrImportFailed(fmt.Sprintf("the vector container '%s' should be a JSON object, got '%v'", key, value))
+	}
+	names := lo.Map(r.option.containerFields, func(fieldID int64, _ int) string {
+		return r.id2Field[fieldID].GetName()
+	})
+	missing := lo.Filter(names, func(name string, _ int) bool {
+		_, ok := container[name]
+		return !ok
+	})
+	if len(missing) > 0 {
+		field := r.id2Field[r.name2FieldID[missing[0]]]
+		return nil, newParseError(ErrKindMissingField, field, nil, merr.WrapErrImportFailed(
+			fmt.Sprintf("vectors %v are missed in the vector container '%s'", missing, key)))
+	}
+	for inner := range container {
+		if !lo.Contains(names, inner) {
+			return nil, newParseError(ErrKindUnknownField, nil, nil, merr.WrapErrImportFailed(
+				fmt.Sprintf("unexpected key '%s' in the vector container '%s', expected keys: %v", inner, key, names)))
+		}
+	}
+	res := make(map[string]any, len(stringMap)+len(names))
+	for k, v := range stringMap {
+		res[k] = v
+	}
+	delete(res, key)
+	for _, name := range names {
+		if _, ok := r.lookupValue(stringMap, name); ok {
+			return nil, merr.WrapErrImportFailed(fmt.Sprintf("the vector field '%s' is provided by both the field and the vector container", name))
+		}
+		res[name] = container[name]
+	}
+	return res, nil
+}
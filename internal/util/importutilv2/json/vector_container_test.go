@@ -0,0 +1,74 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/common"
+)
+
+func TestRowParser_VectorContainer(t *testing.T) {
+	schema := newTestSchema(&schemapb.FieldSchema{
+		FieldID:    102,
+		Name:       "txt",
+		DataType:   schemapb.DataType_FloatVector,
+		TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "3"}},
+	})
+	parser, err := NewRowParser(schema, WithVectorContainer("vectors"))
+	assert.NoError(t, err)
+
+	raw := decodeRow(t, `{"id": 1, "vectors": {"vector": [0.1, 0.2], "txt": [0.3, 0.4, 0.5]}}`)
+	row, err := parser.Parse(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.1, 0.2}, row[101])
+	assert.Equal(t, []float32{0.3, 0.4, 0.5}, row[102])
+	// the input is not modified
+	assert.Contains(t, raw.(map[string]any), "vectors")
+
+	// the vectors can still be provided at the top level by the rows without the container
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "txt": [0.3, 0.4, 0.5]}`))
+	assert.NoError(t, err)
+
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vectors": {"vector": [0.1, 0.2]}}`))
+	assert.ErrorContains(t, err, "vectors [txt] are missed in the vector container 'vectors'")
+	var parseErr *ParseError
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, ErrKindMissingField, parseErr.Kind)
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vectors": {"vector": [0.1, 0.2], "txt": [0.3, 0.4, 0.5], "img": [1]}}`))
+	assert.ErrorContains(t, err, "unexpected key 'img' in the vector container 'vectors'")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "vectors": {"vector": [0.1, 0.2], "txt": [0.3, 0.4, 0.5]}}`))
+	assert.ErrorContains(t, err, "provided by both the field and the vector container")
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vectors": [0.1, 0.2]}`))
+	assert.ErrorContains(t, err, "should be a JSON object")
+
+	// only the given fields are read from the container
+	parser, err = NewRowParser(schema, WithVectorContainer("vectors", 102))
+	assert.NoError(t, err)
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.1, 0.2], "vectors": {"txt": [0.3, 0.4, 0.5]}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.3, 0.4, 0.5}, row[102])
+
+	_, err = NewRowParser(schema, WithVectorContainer("vectors", 100))
+	assert.ErrorContains(t, err, "vector container is only supported for vector field")
+	_, err = NewRowParser(schema, WithVectorContainer("txt"))
+	assert.ErrorContains(t, err, "conflicts with the field of the same name")
+}
@@ -0,0 +1,56 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/util/merr"
+)
+
+func (r *rowParser) checkVectorNorms() error {
+	for normFieldID, vectorFieldID := range r.option.vectorNorms {
+		if r.id2Field[vectorFieldID].GetDataType() != schemapb.DataType_FloatVector {
+			return merr.WrapErrImportFailed(fmt.Sprintf("norm is only supported for FloatVector field, field id: %d", vectorFieldID))
+		}
+		dataType := r.id2Field[normFieldID].GetDataType()
+		if dataType != schemapb.DataType_Float && dataType != schemapb.DataType_Double {
+			return merr.WrapErrImportFailed(fmt.Sprintf("norm can only be stored in Float or Double field, field id: %d", normFieldID))
+		}
+	}
+	return nil
+}
+
+// vectorNorm returns the L2 norm of the parsed FloatVector as a json.Number, the form
+// expected from computed fields.
+func vectorNorm(value any) (any, error) {
+	vec, ok := value.([]float32)
+	if !ok {
+		return nil, fmt.Errorf("expected a parsed float vector, got '%T'", value)
+	}
+	var sum float64
+	for _, v := range vec {
+		sum += float64(v) * float64(v)
+	}
+	norm, ok := floatToNumber(math.Sqrt(sum))
+	if !ok {
+		return nil, fmt.Errorf("the norm of the vector is not finite")
+	}
+	return norm, nil
+}
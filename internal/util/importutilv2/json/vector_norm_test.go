@@ -0,0 +1,52 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestRowParser_VectorNorm(t *testing.T) {
+	schema := newTestSchema(
+		&schemapb.FieldSchema{FieldID: 102, Name: "norm", DataType: schemapb.DataType_Float},
+		&schemapb.FieldSchema{FieldID: 103, Name: "norm64", DataType: schemapb.DataType_Double},
+	)
+	parser, err := NewRowParser(schema, WithVectorNorm(101, 102), WithVectorNorm(101, 103))
+	assert.NoError(t, err)
+
+	row, err := parser.Parse(decodeRow(t, `{"id": 1, "vector": [3, 4]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, float32(5), row[102])
+	assert.Equal(t, float64(5), row[103])
+
+	row, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [0.5, 0.5]}`))
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.70710678, row[103], 1e-7)
+
+	// the norm field is computed, it's not allowed in the input
+	_, err = parser.Parse(decodeRow(t, `{"id": 1, "vector": [3, 4], "norm": 5}`))
+	assert.ErrorContains(t, err, "the field 'norm' is computed, no need to provide")
+
+	_, err = NewRowParser(schema, WithVectorNorm(100, 102))
+	assert.ErrorContains(t, err, "norm is only supported for FloatVector field")
+	_, err = NewRowParser(schema, WithVectorNorm(101, 100))
+	assert.ErrorContains(t, err, "norm can only be stored in Float or Double field")
+}